@@ -0,0 +1,146 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// adminCmd represents the admin command
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Server admin management.",
+	Long: `Manage the server admins, the users and Steam groups granted a permission
+level. Lower levels grant more permissions, 0 being the highest.`,
+}
+
+// adminListCmd represents the admin list command
+var adminListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the server admins.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		admins, err := Client.GetAdminsContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := pterm.TableData{{"Type", "Name", "ID", "Permission Level"}}
+		for _, user := range admins.Users {
+			table = append(table, []string{
+				"User",
+				user.Name,
+				user.UserID,
+				fmt.Sprintf("%v", user.PermissionLevel),
+			})
+		}
+		for _, group := range admins.Groups {
+			table = append(table, []string{
+				"Group",
+				group.Name,
+				group.GroupID,
+				fmt.Sprintf("%v (officers: %v)", group.PermissionLevelNormal, group.PermissionLevelMods),
+			})
+		}
+
+		return renderOutput(admins, table)
+	},
+}
+
+// adminAddCmd represents the admin add command
+var adminAddCmd = &cobra.Command{
+	Use:   "add <id> <level> [name]",
+	Short: "Add an admin or change their permission level.",
+	Long: `Grant a user, identified by their platform ID, the given permission level.
+With --group, grant the members of a Steam group the permission level instead,
+and --mods-level the level of the group's officers.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		level, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid permission level %q", args[1])
+		}
+		name := ""
+		if len(args) > 2 {
+			name = args[2]
+		}
+
+		if !viper.GetBool("admin.add.group") {
+			err = Client.AddAdminUserContext(cmd.Context(), id, name, level)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Set the permission level of user %v to %v.\n", id, level)
+			return nil
+		}
+
+		modsLevel := level
+		if cmd.Flags().Changed("mods-level") {
+			modsLevel = viper.GetInt("admin.add.mods-level")
+		}
+		err = Client.AddAdminGroupContext(cmd.Context(), id, name, level, modsLevel)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Set the permission level of group %v to %v (officers: %v).\n", id, level, modsLevel)
+		return nil
+	},
+}
+
+// adminRemoveCmd represents the admin remove command
+var adminRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove an admin.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if viper.GetBool("admin.remove.group") {
+			err := Client.DeleteAdminGroupContext(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed group %v from the admins.\n", args[0])
+			return nil
+		}
+
+		err := Client.DeleteAdminUserContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed user %v from the admins.\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminListCmd)
+	adminCmd.AddCommand(adminAddCmd)
+	adminCmd.AddCommand(adminRemoveCmd)
+
+	adminAddCmd.Flags().BoolP("group", "g", false, "The ID is a Steam group ID.")
+	adminAddCmd.Flags().Int("mods-level", 0, "Permission level of the group's officers. Defaults to the level of regular members.")
+	adminRemoveCmd.Flags().BoolP("group", "g", false, "The ID is a Steam group ID.")
+
+	viper.BindPFlag("admin.add.group", adminAddCmd.Flags().Lookup("group"))
+	viper.BindPFlag("admin.add.mods-level", adminAddCmd.Flags().Lookup("mods-level"))
+	viper.BindPFlag("admin.remove.group", adminRemoveCmd.Flags().Lookup("group"))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// blacklistCmd represents the ban command
+var blacklistCmd = &cobra.Command{
+	Use:   "ban",
+	Short: "Ban (blacklist) management.",
+}
+
+// banListCmd represents the ban list command
+var banListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the banned players.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bans, err := Client.GetBansContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := pterm.TableData{{"Name", "User ID", "Until", "Remaining", "Reason"}}
+		now := time.Now()
+		for _, ban := range bans.Data.Bans {
+			remaining := "expired"
+			if ban.Until.After(now) {
+				remaining = sdtdclient.SecondsToDaysHoursMinutesSeconds(int(ban.Until.Sub(now).Seconds()))
+			}
+			table = append(table, []string{
+				ban.Name,
+				ban.UserID,
+				ban.Until.Local().Format(time.DateTime),
+				remaining,
+				ban.Reason,
+			})
+		}
+
+		return renderOutput(bans.Data, table)
+	},
+}
+
+// banAddCmd represents the ban add command
+var banAddCmd = &cobra.Command{
+	Use:   "add <user id> <duration> [reason...]",
+	Short: "Ban a player.",
+	Long: `Ban a player for the given duration, e.g. 30m, 12h, 7d or 2w, with an
+optional reason shown to the player.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		duration, err := sdtdclient.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return fmt.Errorf("%w: the ban duration must be positive", sdtdclient.ErrInvalidDuration)
+		}
+		reason := strings.Join(args[2:], " ")
+		until := time.Now().Add(duration)
+
+		err = Client.AddBanContext(cmd.Context(), id, viper.GetString("ban.add.name"), until, reason)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Banned %v until %s.\n", id, until.Format(time.DateTime))
+		return nil
+	},
+}
+
+// banRemoveCmd represents the ban remove command
+var banRemoveCmd = &cobra.Command{
+	Use:   "remove <user id>",
+	Short: "Lift the ban of a player.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := Client.DeleteBanContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed the ban of %v.\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(blacklistCmd)
+	blacklistCmd.AddCommand(banListCmd)
+	blacklistCmd.AddCommand(banAddCmd)
+	blacklistCmd.AddCommand(banRemoveCmd)
+
+	banAddCmd.Flags().StringP("name", "N", "", "The name of the banned player, for reference.")
+	viper.BindPFlag("ban.add.name", banAddCmd.Flags().Lookup("name"))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// commandCmd represents the command command
+var commandCmd = &cobra.Command{
+	Use:   "command",
+	Short: "Console commands.",
+}
+
+// commandRunCmd represents the command run command
+var commandRunCmd = &cobra.Command{
+	Use:   "run <command...>",
+	Short: "Run a console command and print its output.",
+	Long: `Runs a console command on the server and prints its output. The
+arguments are joined with spaces, e.g.
+
+  sdtd_client command run say "\"Server restarting soon\""`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := Client.ExecuteCommandContext(cmd.Context(), strings.Join(args, " "))
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// commandListCmd represents the command list command
+var commandListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the console commands available on the server.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := Client.GetCommandsContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		showHelp := viper.GetBool("command.list.help")
+		headers := []string{"Command", "Aliases", "Description"}
+		if showHelp {
+			headers = append(headers, "Help")
+		}
+		table := pterm.TableData{headers}
+		for idx := range resp.Data.Commands {
+			command := &resp.Data.Commands[idx]
+			row := []string{
+				command.Command,
+				strings.Join(command.Overloads, ", "),
+				command.Description,
+			}
+			if showHelp {
+				row = append(row, strings.TrimSpace(command.Help))
+			}
+			table = append(table, row)
+		}
+
+		return renderOutput(resp.Data.Commands, table)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commandCmd)
+	commandCmd.AddCommand(commandRunCmd)
+	commandCmd.AddCommand(commandListCmd)
+
+	commandListCmd.Flags().Bool("help-text", false, "Include the detailed help text of each command.")
+	viper.BindPFlag("command.list.help", commandListCmd.Flags().Lookup("help-text"))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// Fetches entities from the vanilla API, falling back to Alloc's Server Fixes
+// when the vanilla module is not available.
+func fetchEntities(
+	ctx context.Context,
+	vanilla func(context.Context) (*sdtdclient.EntitiesResponse, error),
+	allocs func(context.Context) (*sdtdclient.EntitiesResponseM, error),
+) ([]sdtdclient.EntityData, error) {
+	resp, err := vanilla(ctx)
+	if err == nil {
+		return resp.Data, nil
+	}
+	if !Client.AllocsEnabled() ||
+		!(errors.Is(err, sdtdclient.ErrCapabilityMissing) || errors.Is(err, sdtdclient.ErrNotFound)) {
+		return nil, err
+	}
+
+	respM, err := allocs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return *respM, nil
+}
+
+// An entity with its distance from the reference location.
+type entityDistance struct {
+	sdtdclient.EntityData
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+// Creates a command listing entities of the given kind.
+func newEntityListCmd(
+	kind string,
+	vanilla func(context.Context) (*sdtdclient.EntitiesResponse, error),
+	allocs func(context.Context) (*sdtdclient.EntitiesResponseM, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   kind,
+		Short: fmt.Sprintf("List the %s currently alive.", kind),
+		Long: fmt.Sprintf(`List the %s currently alive, with their location and health. With
+--from, the %s are sorted by distance from the given "x,z" or "x,y,z"
+coordinates, closest first. Without a height, only the horizontal distance is
+used.`, kind, kind),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entities, err := fetchEntities(cmd.Context(), vanilla, allocs)
+			if err != nil {
+				return err
+			}
+
+			list := make([]entityDistance, len(entities))
+			for idx := range entities {
+				list[idx].EntityData = entities[idx]
+			}
+
+			header := []string{"ID", "Name", "Location", "Health"}
+			if from := viper.GetString("server." + kind + ".from"); from != "" {
+				origin, hasY, err := parseCoords(from)
+				if err != nil {
+					return err
+				}
+				for idx := range list {
+					if !hasY {
+						// Measure the horizontal distance only.
+						origin.Y = list[idx].Position.Y
+					}
+					distance := list[idx].Position.DistanceTo(origin)
+					list[idx].Distance = &distance
+				}
+				slices.SortStableFunc(list, func(a, b entityDistance) int {
+					return cmp.Compare(*a.Distance, *b.Distance)
+				})
+				header = append(header, "Distance")
+			} else {
+				slices.SortFunc(list, func(a, b entityDistance) int {
+					return cmp.Compare(a.ID, b.ID)
+				})
+			}
+
+			if limit := viper.GetInt("server." + kind + ".limit"); limit > 0 && limit < len(list) {
+				list = list[:limit]
+			}
+
+			table := pterm.TableData{header}
+			for _, entity := range list {
+				row := []string{
+					fmt.Sprintf("%v", entity.ID),
+					entity.Name,
+					entity.Position.GetCoordinates(),
+					fmt.Sprintf("%v", entity.Health),
+				}
+				if entity.Distance != nil {
+					row = append(row, fmt.Sprintf("%.0f", *entity.Distance))
+				}
+				table = append(table, row)
+			}
+			return renderOutput(list, table)
+		},
+	}
+
+	cmd.Flags().String("from", "", `Sort by distance from these "x,z" or "x,y,z" coordinates.`)
+	cmd.Flags().Int("limit", 0, "Only list this many entities, 0 for all.")
+	viper.BindPFlag("server."+kind+".from", cmd.Flags().Lookup("from"))
+	viper.BindPFlag("server."+kind+".limit", cmd.Flags().Lookup("limit"))
+	return cmd
+}
+
+func init() {
+	serverCmd.AddCommand(newEntityListCmd(
+		"hostiles",
+		func(ctx context.Context) (*sdtdclient.EntitiesResponse, error) { return Client.GetHostilesContext(ctx) },
+		func(ctx context.Context) (*sdtdclient.EntitiesResponseM, error) {
+			return Client.GetHostilesMContext(ctx)
+		},
+	))
+	serverCmd.AddCommand(newEntityListCmd(
+		"animals",
+		func(ctx context.Context) (*sdtdclient.EntitiesResponse, error) { return Client.GetAnimalsContext(ctx) },
+		func(ctx context.Context) (*sdtdclient.EntitiesResponseM, error) {
+			return Client.GetAnimalsMContext(ctx)
+		},
+	))
+}
@@ -16,8 +16,15 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-kit/log/level"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -28,7 +35,18 @@ import (
 var logCmd = &cobra.Command{
 	Use:   "log",
 	Short: "Retrieve the server logs.",
+	Long: `Retrieve the server logs. With --follow, keeps printing new log lines as
+they are written, streamed by the server when supported and polled otherwise.
+With --checkpoint, the log is polled and the position is saved to the given
+file, so a later run resumes where the previous one stopped.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if viper.GetBool("log.follow") {
+			if interval := viper.GetDuration("log.interval"); interval <= 0 {
+				return fmt.Errorf("invalid interval %v, must be positive", interval)
+			}
+			return followLog(cmd.Context())
+		}
+
 		var count, firstLine *int
 
 		if viper.GetString("log.count") != "" {
@@ -41,7 +59,7 @@ var logCmd = &cobra.Command{
 			*firstLine = viper.GetInt("log.firstline")
 		}
 
-		log, err := Client.GetLog(count, firstLine)
+		log, err := Client.GetLogContext(cmd.Context(), count, firstLine)
 		if err != nil {
 			return err
 		}
@@ -64,11 +82,92 @@ var logCmd = &cobra.Command{
 			})
 		}
 
-		pterm.DefaultTable.WithBoxed().WithHasHeader().WithData(tableData).Render()
-		return nil
+		return renderOutput(log.Data.Entries, tableData)
 	},
 }
 
+// Print new log lines until the context is cancelled. When resuming from a
+// checkpoint file the log is always polled, since only polling tracks line
+// numbers.
+func followLog(ctx context.Context) error {
+	checkpoint := viper.GetString("log.checkpoint")
+	if checkpoint == "" {
+		stream, err := Client.SubscribeLog(ctx)
+		if errors.Is(err, sdtdclient.ErrNotFound) {
+			level.Info(logger).Log("msg", "Log streaming not available, polling the log instead")
+		} else if err != nil {
+			return err
+		} else {
+			for entry := range stream.Entries {
+				printLogEntry(&entry)
+			}
+			return stream.Err()
+		}
+	}
+
+	startLine, err := readCheckpoint(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	follower := Client.NewLogFollower(startLine)
+	follower.Interval = viper.GetDuration("log.interval")
+
+	// Save the line after each printed entry, so that a run stopped at any
+	// point resumes without printing an entry twice.
+	err = follower.Run(ctx, func(entry sdtdclient.LogEntry) error {
+		printLogEntry(&entry)
+		if checkpoint != "" {
+			return writeCheckpoint(checkpoint, entry.ID+1)
+		}
+		return nil
+	})
+	if checkpoint != "" && follower.NextLine() >= 0 {
+		if saveErr := writeCheckpoint(checkpoint, follower.NextLine()); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	return err
+}
+
+// Read the next line number to fetch from a checkpoint file. Returns nil if no
+// file is given or it does not exist yet.
+func readCheckpoint(path string) (*int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	line, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %w", path, err)
+	}
+	return &line, nil
+}
+
+// Save the next line number to fetch to a checkpoint file. The file is
+// replaced atomically so an interrupted write never leaves it truncated.
+func writeCheckpoint(path string, line int) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(line)+"\n"), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Print a log entry on a single line.
+func printLogEntry(entry *sdtdclient.LogEntry) {
+	fmt.Printf("%s %s %s\n", entry.IsoTime, entry.Type, entry.Msg)
+	if entry.Trace != "" {
+		fmt.Println(entry.Trace)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(logCmd)
 
@@ -89,6 +188,13 @@ Defaults to the oldest stored log line if count is positive.
 Defaults to the most recent log line if count is negative`,
 	)
 
+	logCmd.Flags().BoolP("follow", "f", false, "Keep printing new log lines as they are written.")
+	logCmd.Flags().Duration("interval", 2*time.Second, "How often to poll for new lines when the server cannot stream them.")
+	logCmd.Flags().String("checkpoint", "", "File storing the log position, to resume following from where the last run stopped.")
+
 	viper.BindPFlag("log.count", logCmd.Flags().Lookup("count"))
+	viper.BindPFlag("log.checkpoint", logCmd.Flags().Lookup("checkpoint"))
+	viper.BindPFlag("log.follow", logCmd.Flags().Lookup("follow"))
+	viper.BindPFlag("log.interval", logCmd.Flags().Lookup("interval"))
 	viper.BindPFlag("log.firstline", logCmd.Flags().Lookup("firstline"))
 }
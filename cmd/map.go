@@ -0,0 +1,124 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// mapCmd represents the map command
+var mapCmd = &cobra.Command{
+	Use:   "map",
+	Short: "Web map commands.",
+}
+
+// Returns the corners of the map area selected by the --from and --to flags of
+// a command, under the given viper key prefix. Defaults to the whole world
+// when the server reports its generated world size.
+func mapArea(ctx context.Context, prefix string) (sdtdclient.Location, sdtdclient.Location, error) {
+	fromFlag, toFlag := viper.GetString(prefix+".from"), viper.GetString(prefix+".to")
+	if fromFlag != "" && toFlag != "" {
+		from, _, err := parseCoords(fromFlag)
+		if err != nil {
+			return from, from, err
+		}
+		to, _, err := parseCoords(toFlag)
+		return from, to, err
+	} else if fromFlag != "" || toFlag != "" {
+		return sdtdclient.Location{}, sdtdclient.Location{}, fmt.Errorf("--from and --to must be given together")
+	}
+
+	prefs, err := Client.GetGamePrefsContext(ctx)
+	if err != nil {
+		return sdtdclient.Location{}, sdtdclient.Location{}, err
+	}
+	for _, pref := range prefs.Data {
+		if pref.Name != "WorldGenSize" {
+			continue
+		}
+		size, err := strconv.Atoi(fmt.Sprintf("%v", pref.Value))
+		if err != nil || size <= 0 {
+			break
+		}
+		half := size / 2
+		return sdtdclient.Location{X: -half, Z: -half}, sdtdclient.Location{X: half - 1, Z: half - 1}, nil
+	}
+	return sdtdclient.Location{}, sdtdclient.Location{}, fmt.Errorf("the world size is unknown, select the area with --from and --to")
+}
+
+// Returns the tiles covering the area at the highest zoom level, up to
+// maxZoom, whose stitched image fits within maxSize pixels on each side.
+func fitTileBounds(from, to sdtdclient.Location, maxZoom, maxSize int) (sdtdclient.TileBounds, error) {
+	for zoom := maxZoom; zoom >= 0; zoom-- {
+		bounds, err := sdtdclient.TileBoundsFor(zoom, from, to)
+		if err != nil {
+			return bounds, err
+		}
+		width, height := bounds.Size()
+		if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+			return bounds, nil
+		}
+	}
+	return sdtdclient.TileBounds{}, fmt.Errorf("the map does not fit within %d pixels even at zoom level 0", maxSize)
+}
+
+// Returns the tile cache selected by the flags of a command, under the given
+// viper key prefix, or nil if caching is disabled. Defaults to a directory per
+// server in the user's cache directory.
+func tileCache(prefix string) (*sdtdclient.DirTileCache, error) {
+	if viper.GetBool(prefix + ".no-cache") {
+		return nil, nil
+	}
+	if dir := viper.GetString(prefix + ".cache-dir"); dir != "" {
+		return &sdtdclient.DirTileCache{Dir: dir}, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	host := strings.NewReplacer("://", "_", ":", "_", "/", "_").Replace(Client.Host)
+	return &sdtdclient.DirTileCache{Dir: filepath.Join(dir, "sdtd_client", "tiles", host)}, nil
+}
+
+// Adds the flags selecting the map area and tiles to a command, bound to viper
+// keys under the given prefix.
+func addMapFlags(cmd *cobra.Command, prefix string) {
+	cmd.Flags().String("from", "", `One corner of the area, as "x,z" or "x,y,z" world coordinates. Defaults to the whole world.`)
+	cmd.Flags().String("to", "", `The opposite corner of the area, as "x,z" or "x,y,z" world coordinates.`)
+	cmd.Flags().Int("zoom", sdtdclient.MapMaxZoom, "Zoom level of the tiles, lowered as needed to honor --max-size.")
+	cmd.Flags().Int("max-size", 8192, "Maximum width and height of the image in pixels, 0 for no limit.")
+	cmd.Flags().Int("concurrency", 4, "Number of tiles fetched at once.")
+	cmd.Flags().String("cache-dir", "", "Directory caching the tiles between runs. Defaults to the user's cache directory.")
+	cmd.Flags().Bool("no-cache", false, "Do not cache the tiles.")
+
+	for _, name := range []string{"from", "to", "zoom", "max-size", "concurrency", "cache-dir", "no-cache"} {
+		viper.BindPFlag(prefix+"."+name, cmd.Flags().Lookup(name))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mapCmd)
+}
@@ -0,0 +1,318 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+	"gopkg.in/yaml.v3"
+)
+
+// A marker of a marker import file.
+type markerEntry struct {
+	Name string `yaml:"name"`
+	Icon string `yaml:"icon"`
+	X    int    `yaml:"x"`
+	Y    int    `yaml:"y"`
+	Z    int    `yaml:"z"`
+}
+
+// markerCmd represents the map marker command
+var markerCmd = &cobra.Command{
+	Use:   "marker",
+	Short: "Web map marker management.",
+}
+
+// markerListCmd represents the map marker list command
+var markerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the markers of the web map.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		markers, err := Client.GetMapMarkersContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := pterm.TableData{{"ID", "Name", "Location", "Icon"}}
+		for _, marker := range markers.Data {
+			table = append(table, []string{
+				marker.ID,
+				marker.Name,
+				marker.Position.GetCoordinates(),
+				marker.Icon,
+			})
+		}
+		return renderOutput(markers.Data, table)
+	},
+}
+
+// markerAddCmd represents the map marker add command
+var markerAddCmd = &cobra.Command{
+	Use:   "add <name> <x> <y> <z>",
+	Short: "Add a marker to the web map.",
+	Long: `Add a marker to the web map at the given world coordinates.
+
+Separate the arguments with -- when a coordinate is negative, e.g.
+
+  sdtd_client map marker add Trader -- -1200 60 850`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		location, err := parseLocation(args[1:])
+		if err != nil {
+			return err
+		}
+
+		marker, err := Client.CreateMapMarkerContext(cmd.Context(), sdtdclient.MapMarker{
+			Name:     args[0],
+			Icon:     viper.GetString("map.marker.add.icon"),
+			Position: location,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added marker '%s' (%v) at %s.\n", marker.Name, marker.ID, marker.Position.GetCoordinates())
+		return nil
+	},
+}
+
+// markerUpdateCmd represents the map marker update command
+var markerUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Change the name, icon or location of a marker.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		markers, err := Client.GetMapMarkersContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var marker *sdtdclient.MapMarker
+		for idx := range markers.Data {
+			if markers.Data[idx].ID == args[0] {
+				marker = &markers.Data[idx]
+				break
+			}
+		}
+		if marker == nil {
+			return fmt.Errorf("no marker with ID %v", args[0])
+		}
+
+		if cmd.Flags().Changed("name") {
+			marker.Name = viper.GetString("map.marker.update.name")
+		}
+		if cmd.Flags().Changed("icon") {
+			marker.Icon = viper.GetString("map.marker.update.icon")
+		}
+		if cmd.Flags().Changed("position") {
+			position := viper.GetString("map.marker.update.position")
+			location, hasY, err := parseCoords(position)
+			if err != nil {
+				return err
+			}
+			if !hasY {
+				return fmt.Errorf(`invalid coordinates %q, expected "x,y,z"`, position)
+			}
+			marker.Position = location
+		}
+
+		err = Client.UpdateMapMarkerContext(cmd.Context(), *marker)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated marker '%s' (%v).\n", marker.Name, marker.ID)
+		return nil
+	},
+}
+
+// markerDeleteCmd represents the map marker delete command
+var markerDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Remove a marker from the web map.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := Client.DeleteMapMarkerContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted marker %v.\n", args[0])
+		return nil
+	},
+}
+
+// markerImportCmd represents the map marker import command
+var markerImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add the markers listed in a file to the web map.",
+	Long: `Add the markers listed in a file to the web map. Markers with the same name
+and location as an existing marker are skipped, so a file can be imported again
+after it was extended.
+
+The file is either a YAML file:
+
+  markers:
+    - name: Trader Rekt
+      x: -512
+      y: 40
+      z: 310
+      icon: https://example.com/trader.png
+
+or a CSV file (with a .csv extension) with name, x, y, z and optional icon
+columns:
+
+  name,x,y,z,icon
+  Trader Rekt,-512,40,310,https://example.com/trader.png`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadMarkers(args[0])
+		if err != nil {
+			return err
+		}
+
+		markers, err := Client.GetMapMarkersContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		existing := map[string]bool{}
+		for _, marker := range markers.Data {
+			existing[marker.Name+" "+marker.Position.GetCoordinates()] = true
+		}
+
+		added, skipped := 0, 0
+		for _, marker := range entries {
+			key := marker.Name + " " + marker.Position.GetCoordinates()
+			if existing[key] {
+				skipped++
+				continue
+			}
+
+			if _, err := Client.CreateMapMarkerContext(cmd.Context(), marker); err != nil {
+				return fmt.Errorf("adding marker '%s': %w", marker.Name, err)
+			}
+			existing[key] = true
+			added++
+		}
+
+		fmt.Printf("Added %d markers, skipped %d existing markers.\n", added, skipped)
+		return nil
+	},
+}
+
+// Load a marker import file, in CSV format if it has a .csv extension and YAML
+// otherwise.
+func loadMarkers(path string) ([]sdtdclient.MapMarker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []markerEntry
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = readMarkersCSV(file)
+	} else {
+		var doc struct {
+			Markers []markerEntry `yaml:"markers"`
+		}
+		err = yaml.NewDecoder(file).Decode(&doc)
+		if err == io.EOF {
+			err = nil
+		}
+		entries = doc.Markers
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading markers %s: %w", path, err)
+	}
+
+	markers := make([]sdtdclient.MapMarker, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("markers %s: marker without a name", path)
+		}
+		markers = append(markers, sdtdclient.MapMarker{
+			Name:     entry.Name,
+			Icon:     entry.Icon,
+			Position: sdtdclient.Location{X: entry.X, Y: entry.Y, Z: entry.Z},
+		})
+	}
+	return markers, nil
+}
+
+// Read a CSV marker file with name, x, y, z and optional icon columns, and an
+// optional header.
+func readMarkersCSV(r io.Reader) ([]markerEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []markerEntry{}
+	for idx, record := range records {
+		if idx == 0 && strings.EqualFold(record[0], "name") {
+			continue
+		}
+		if len(record) < 4 || len(record) > 5 {
+			return nil, fmt.Errorf("line %d: expected 4 or 5 columns, got %d", idx+1, len(record))
+		}
+
+		location, err := parseLocation(record[1:4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", idx+1, err)
+		}
+		entry := markerEntry{Name: record[0], X: location.X, Y: location.Y, Z: location.Z}
+		if len(record) == 5 {
+			entry.Icon = record[4]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func init() {
+	mapCmd.AddCommand(markerCmd)
+	markerCmd.AddCommand(markerListCmd)
+	markerCmd.AddCommand(markerAddCmd)
+	markerCmd.AddCommand(markerUpdateCmd)
+	markerCmd.AddCommand(markerDeleteCmd)
+	markerCmd.AddCommand(markerImportCmd)
+
+	markerAddCmd.Flags().String("icon", "", "URL of the marker icon.")
+	markerUpdateCmd.Flags().String("name", "", "The new name of the marker.")
+	markerUpdateCmd.Flags().String("icon", "", "URL of the new marker icon.")
+	markerUpdateCmd.Flags().String("position", "", `The new "x,y,z" coordinates of the marker.`)
+
+	viper.BindPFlag("map.marker.add.icon", markerAddCmd.Flags().Lookup("icon"))
+	viper.BindPFlag("map.marker.update.name", markerUpdateCmd.Flags().Lookup("name"))
+	viper.BindPFlag("map.marker.update.icon", markerUpdateCmd.Flags().Lookup("icon"))
+	viper.BindPFlag("map.marker.update.position", markerUpdateCmd.Flags().Lookup("position"))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// renderCmd represents the map render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render the explored map to a PNG image.",
+	Long: `Download the tiles of the web map covering an area and stitch them into a
+single PNG image, north up. Tiles are cached on disk and only downloaded again
+when they changed on the server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		img, _, err := renderMapTiles(cmd.Context(), "map.render")
+		if err != nil {
+			return err
+		}
+
+		file := viper.GetString("map.render.file")
+		if err := writePNG(file, img); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote a %dx%d map to %s.\n", img.Bounds().Dx(), img.Bounds().Dy(), file)
+		return nil
+	},
+}
+
+// Fetch and stitch the tiles of the area selected by the map flags of a
+// command, under the given viper key prefix.
+func renderMapTiles(ctx context.Context, prefix string) (*image.RGBA, sdtdclient.TileBounds, error) {
+	from, to, err := mapArea(ctx, prefix)
+	if err != nil {
+		return nil, sdtdclient.TileBounds{}, err
+	}
+	bounds, err := fitTileBounds(from, to, viper.GetInt(prefix+".zoom"), viper.GetInt(prefix+".max-size"))
+	if err != nil {
+		return nil, bounds, err
+	}
+	cache, err := tileCache(prefix)
+	if err != nil {
+		return nil, bounds, err
+	}
+
+	tiles, err := Client.FetchMapTilesContext(ctx, bounds, sdtdclient.TileFetchOptions{
+		Concurrency: viper.GetInt(prefix + ".concurrency"),
+		Cache:       cache,
+	})
+	if err != nil {
+		return nil, bounds, err
+	}
+
+	cached, missing := 0, 0
+	for idx := range tiles {
+		if tiles[idx].Data == nil {
+			missing++
+		} else if tiles[idx].Cached {
+			cached++
+		}
+	}
+	level.Info(logger).Log("msg", "Fetched map tiles", "zoom", bounds.Zoom, "tiles", len(tiles), "cached", cached, "missing", missing)
+
+	img, err := sdtdclient.StitchMapTiles(bounds, tiles)
+	return img, bounds, err
+}
+
+// Write an image to a PNG file.
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func init() {
+	mapCmd.AddCommand(renderCmd)
+
+	addMapFlags(renderCmd, "map.render")
+	renderCmd.Flags().StringP("file", "f", "map.png", "The PNG file to write.")
+	viper.BindPFlag("map.render.file", renderCmd.Flags().Lookup("file"))
+}
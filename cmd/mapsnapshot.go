@@ -0,0 +1,168 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thelande/sdtd_client/pkg/render"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// Overlays that can be drawn on a map snapshot.
+var snapshotOverlays = []string{"players", "hostiles", "claims"}
+
+// Overlays drawn when none are selected. Land claims are left out as they
+// need Alloc's Server Fixes.
+var defaultSnapshotOverlays = []string{"players", "hostiles"}
+
+// snapshotCmd represents the map snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Render the map with the locations of players, hostiles and land claims.",
+	Long: `Render an image of the map showing the online players, clusters of hostiles
+and the areas protected by land claims. The map is drawn from the web map tiles,
+or as a blank grid with --base grid. The image is written as PNG or SVG,
+selected with --format or from the extension of the file.
+
+Players and hostiles are drawn by default. Land claims require Alloc's Server
+Fixes to be installed on the server and must be selected with --overlay, e.g.
+--overlay players,hostiles,claims.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		overlays := viper.GetStringSlice("map.snapshot.overlay")
+		for _, overlay := range overlays {
+			if !slices.Contains(snapshotOverlays, overlay) {
+				return fmt.Errorf("invalid overlay %q, must be one of %s", overlay, strings.Join(snapshotOverlays, ", "))
+			}
+		}
+
+		file := viper.GetString("map.snapshot.file")
+		format := viper.GetString("map.snapshot.format")
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+		}
+		if format != "png" && format != "svg" {
+			return fmt.Errorf("invalid format %q, must be png or svg", format)
+		}
+
+		var snapshot *render.Map
+		switch base := viper.GetString("map.snapshot.base"); base {
+		case "tiles":
+			img, bounds, err := renderMapTiles(ctx, "map.snapshot")
+			if err != nil {
+				return err
+			}
+			snapshot = render.NewMap(bounds, img)
+		case "grid":
+			from, to, err := mapArea(ctx, "map.snapshot")
+			if err != nil {
+				return err
+			}
+			bounds, err := fitTileBounds(from, to, viper.GetInt("map.snapshot.zoom"), viper.GetInt("map.snapshot.max-size"))
+			if err != nil {
+				return err
+			}
+			snapshot = render.NewMap(bounds, nil)
+		default:
+			return fmt.Errorf("invalid base %q, must be tiles or grid", base)
+		}
+
+		if err := addSnapshotOverlays(ctx, snapshot, overlays); err != nil {
+			return err
+		}
+
+		out, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		if format == "svg" {
+			err = snapshot.WriteSVG(out)
+		} else {
+			err = snapshot.WritePNG(out)
+		}
+		if err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+
+		width, height := snapshot.Bounds.Size()
+		fmt.Printf("Wrote a %dx%d snapshot with %d markers to %s.\n", width, height, len(snapshot.Features), file)
+		return nil
+	},
+}
+
+// Fetch the data of the selected overlays and add them to the snapshot.
+func addSnapshotOverlays(ctx context.Context, snapshot *render.Map, overlays []string) error {
+	if slices.Contains(overlays, "claims") {
+		claims, err := Client.GetLandClaimsMContext(ctx)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+		snapshot.AddLandClaims(claims)
+	}
+
+	if slices.Contains(overlays, "hostiles") {
+		hostiles, err := fetchEntities(
+			ctx,
+			func(ctx context.Context) (*sdtdclient.EntitiesResponse, error) { return Client.GetHostilesContext(ctx) },
+			func(ctx context.Context) (*sdtdclient.EntitiesResponseM, error) {
+				return Client.GetHostilesMContext(ctx)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		snapshot.AddHostiles(hostiles, viper.GetInt("map.snapshot.cluster-radius"))
+	}
+
+	if slices.Contains(overlays, "players") {
+		players, err := Client.GetOnlinePlayersContext(ctx)
+		if err != nil {
+			return err
+		}
+		snapshot.AddPlayers(players.Data.Players)
+	}
+	return nil
+}
+
+func init() {
+	mapCmd.AddCommand(snapshotCmd)
+
+	addMapFlags(snapshotCmd, "map.snapshot")
+	snapshotCmd.Flags().StringP("file", "f", "snapshot.png", "The PNG or SVG file to write.")
+	snapshotCmd.Flags().String("format", "", "Image format, png or svg. Defaults to the extension of the file.")
+	snapshotCmd.Flags().StringSlice("overlay", defaultSnapshotOverlays, "Overlays to draw: "+strings.Join(snapshotOverlays, ", ")+".")
+	snapshotCmd.Flags().String("base", "tiles", "Base of the map: tiles of the web map, or a blank grid.")
+	snapshotCmd.Flags().Int("cluster-radius", 32, "Hostiles within this many blocks of each other are shown as one marker.")
+
+	for _, name := range []string{"file", "format", "overlay", "base", "cluster-radius"} {
+		viper.BindPFlag("map.snapshot."+name, snapshotCmd.Flags().Lookup(name))
+	}
+}
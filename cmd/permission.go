@@ -0,0 +1,199 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// permissionCmd represents the permission command
+var permissionCmd = &cobra.Command{
+	Use:   "permission",
+	Short: "Console command and web module permission management.",
+	Long: `Manage the permission levels required to run console commands and to use
+the web API modules. Lower levels grant more permissions, 0 being the highest.`,
+}
+
+// commandPermissionCmd represents the permission command command
+var commandPermissionCmd = &cobra.Command{
+	Use:   "command",
+	Short: "Console command permissions.",
+}
+
+// commandPermissionListCmd represents the permission command list command
+var commandPermissionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the permission level required by each console command.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := Client.GetCommandPermissionsContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		permissions := slices.Clone(resp.Data.Commands)
+		slices.SortFunc(permissions, func(a, b sdtdclient.CommandPermission) int {
+			return strings.Compare(a.Command, b.Command)
+		})
+
+		table := pterm.TableData{{"Command", "Permission Level", "Default"}}
+		for _, permission := range permissions {
+			table = append(table, []string{
+				permission.Command,
+				fmt.Sprintf("%v", permission.PermissionLevel),
+				checkMark(permission.IsDefault),
+			})
+		}
+		return renderOutput(permissions, table)
+	},
+}
+
+// commandPermissionSetCmd represents the permission command set command
+var commandPermissionSetCmd = &cobra.Command{
+	Use:   "set <command> <level>",
+	Short: "Set the permission level required to run a console command.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		level, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid permission level %q", args[1])
+		}
+
+		err = Client.SetCommandPermissionContext(cmd.Context(), args[0], level)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Set the permission level of command %v to %v.\n", args[0], level)
+		return nil
+	},
+}
+
+// commandPermissionResetCmd represents the permission command reset command
+var commandPermissionResetCmd = &cobra.Command{
+	Use:   "reset <command>",
+	Short: "Reset the permission level of a console command to the default.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := Client.ResetCommandPermissionContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reset the permission level of command %v.\n", args[0])
+		return nil
+	},
+}
+
+// modulePermissionCmd represents the permission module command
+var modulePermissionCmd = &cobra.Command{
+	Use:   "module",
+	Short: "Web module permissions.",
+}
+
+// modulePermissionListCmd represents the permission module list command
+var modulePermissionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the permission levels required by each web module.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := Client.GetWebModulePermissionsContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		modules := slices.Clone(resp.Data.Modules)
+		slices.SortFunc(modules, func(a, b sdtdclient.WebModulePermission) int {
+			return strings.Compare(a.Module, b.Module)
+		})
+
+		table := pterm.TableData{{"Module", "GET", "POST", "PUT", "DELETE", "Default"}}
+		for _, module := range modules {
+			table = append(table, []string{
+				module.Module,
+				fmt.Sprintf("%v", module.LevelFor("GET")),
+				fmt.Sprintf("%v", module.LevelFor("POST")),
+				fmt.Sprintf("%v", module.LevelFor("PUT")),
+				fmt.Sprintf("%v", module.LevelFor("DELETE")),
+				checkMark(module.IsDefault),
+			})
+		}
+		return renderOutput(modules, table)
+	},
+}
+
+// modulePermissionSetCmd represents the permission module set command
+var modulePermissionSetCmd = &cobra.Command{
+	Use:   "set <module> <level>",
+	Short: "Set the permission level required to use a web module.",
+	Long: `Set the permission level required to use a web module. Use --method-level
+to require a different level for some HTTP methods, e.g.
+--method-level POST=0,DELETE=0.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		level, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid permission level %q", args[1])
+		}
+		methodLevels, err := cmd.Flags().GetStringToInt("method-level")
+		if err != nil {
+			return err
+		}
+
+		err = Client.SetWebModulePermissionContext(cmd.Context(), args[0], level, methodLevels)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Set the permission level of module %v to %v.\n", args[0], level)
+		return nil
+	},
+}
+
+// modulePermissionResetCmd represents the permission module reset command
+var modulePermissionResetCmd = &cobra.Command{
+	Use:   "reset <module>",
+	Short: "Reset the permission levels of a web module to the defaults.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := Client.ResetWebModulePermissionContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reset the permission levels of module %v.\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(permissionCmd)
+	permissionCmd.AddCommand(commandPermissionCmd)
+	permissionCmd.AddCommand(modulePermissionCmd)
+
+	commandPermissionCmd.AddCommand(commandPermissionListCmd)
+	commandPermissionCmd.AddCommand(commandPermissionSetCmd)
+	commandPermissionCmd.AddCommand(commandPermissionResetCmd)
+
+	modulePermissionCmd.AddCommand(modulePermissionListCmd)
+	modulePermissionCmd.AddCommand(modulePermissionSetCmd)
+	modulePermissionCmd.AddCommand(modulePermissionResetCmd)
+
+	modulePermissionSetCmd.Flags().StringToInt("method-level", nil, "Permission level required for specific HTTP methods, as METHOD=LEVEL pairs.")
+}
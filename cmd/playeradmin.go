@@ -0,0 +1,147 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+const playerArgHelp = `The player can be given as an entity ID, a platform ID (e.g.
+Steam_76561198000000000) or a name.`
+
+// kickCmd represents the player kick command
+var kickCmd = &cobra.Command{
+	Use:   "kick <player> [reason]",
+	Short: "Kick a player from the server.",
+	Long:  playerArgHelp,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var reason string
+		if len(args) > 1 {
+			reason = args[1]
+		}
+
+		result, err := Client.KickContext(cmd.Context(), sdtdclient.ParsePlayerRef(args[0]), reason)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// banCmd represents the player ban command
+var banCmd = &cobra.Command{
+	Use:   "ban <player> <duration> [reason]",
+	Short: "Ban a player from the server.",
+	Long: `Ban a player for the given duration, e.g. 30m, 12h, 7d or 2w.
+
+` + playerArgHelp,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		duration, err := sdtdclient.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+
+		var reason string
+		if len(args) > 2 {
+			reason = args[2]
+		}
+
+		result, err := Client.BanContext(cmd.Context(), sdtdclient.ParsePlayerRef(args[0]), duration, reason)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// teleportCmd represents the player teleport command
+var teleportCmd = &cobra.Command{
+	Use:   "teleport <player> <x> <y> <z>",
+	Short: "Teleport a player to a location.",
+	Long: playerArgHelp + `
+
+Separate the arguments with -- when a coordinate is negative, e.g.
+
+  sdtd_client player teleport Steve -- -1200 60 850`,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		location, err := parseLocation(args[1:])
+		if err != nil {
+			return err
+		}
+
+		result, err := Client.TeleportPlayerContext(cmd.Context(), sdtdclient.ParsePlayerRef(args[0]), location)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// giveCmd represents the player give command
+var giveCmd = &cobra.Command{
+	Use:   "give <player> <item> <count>",
+	Short: "Give items to a player.",
+	Long:  playerArgHelp,
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := strconv.Atoi(args[2])
+		if err != nil {
+			return err
+		}
+
+		result, err := Client.GiveContext(
+			cmd.Context(),
+			sdtdclient.ParsePlayerRef(args[0]),
+			args[1],
+			count,
+			viper.GetInt("player.give.quality"),
+		)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+func init() {
+	playerCmd.AddCommand(kickCmd)
+	playerCmd.AddCommand(banCmd)
+	playerCmd.AddCommand(teleportCmd)
+	playerCmd.AddCommand(giveCmd)
+
+	giveCmd.Flags().IntP("quality", "q", 0, "Quality of the items (default quality if 0).")
+	viper.BindPFlag("player.give.quality", giveCmd.Flags().Lookup("quality"))
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// inventoryCmd represents the player inventory command
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory <player>",
+	Short: "Show the items a player carries.",
+	Long: `Show the items in a player's belt and backpack and the items they wear.
+Requires Alloc's Server Fixes Mod.
+
+` + playerArgHelp,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		platformID, err := resolvePlatformID(cmd.Context(), sdtdclient.ParsePlayerRef(args[0]))
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+		inventory, err := Client.GetPlayerInventoryMContext(cmd.Context(), platformID)
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		if viper.GetString("output") == outputTable {
+			fmt.Printf("Inventory of %s (%s):\n", inventory.Name, inventory.PlatformID)
+		}
+		table := pterm.TableData{{"Slot", "Item", "Count", "Quality"}}
+		addItems := func(slot string, items []*sdtdclient.InventoryItemM) {
+			for idx, item := range items {
+				table = appendItem(table, fmt.Sprintf("%s %d", slot, idx+1), item)
+			}
+		}
+		addItems("Belt", inventory.Belt)
+		addItems("Bag", inventory.Bag)
+
+		equipment := &inventory.Equipment
+		for _, slot := range []struct {
+			name string
+			item *sdtdclient.InventoryItemM
+		}{
+			{"Head", equipment.Head},
+			{"Eyes", equipment.Eyes},
+			{"Face", equipment.Face},
+			{"Armor", equipment.Armor},
+			{"Jacket", equipment.Jacket},
+			{"Shirt", equipment.Shirt},
+			{"Leg Armor", equipment.LegArmor},
+			{"Pants", equipment.Pants},
+			{"Boots", equipment.Boots},
+			{"Gloves", equipment.Gloves},
+		} {
+			table = appendItem(table, slot.name, slot.item)
+		}
+		return renderOutput(inventory, table)
+	},
+}
+
+// Resolves a player reference to a platform ID. Players given by entity ID or
+// name are looked up in the list of all players known to the server, matching
+// names case-insensitively as the console commands do.
+func resolvePlatformID(ctx context.Context, player sdtdclient.PlayerRef) (string, error) {
+	if id, ok := player.(sdtdclient.PlatformID); ok {
+		return string(id), nil
+	}
+
+	resp, err := Client.GetAllPlayersMContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	for idx := range resp.Players {
+		known := &resp.Players[idx]
+		switch ref := player.(type) {
+		case sdtdclient.EntityID:
+			if known.EntityID == int(ref) {
+				return known.PlatformID, nil
+			}
+		case sdtdclient.PlayerName:
+			if strings.EqualFold(known.Name, string(ref)) {
+				return known.PlatformID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("player %v not found", player)
+}
+
+// Appends a row for the item in a slot to the table, skipping empty slots.
+func appendItem(table pterm.TableData, slot string, item *sdtdclient.InventoryItemM) pterm.TableData {
+	if item == nil {
+		return table
+	}
+	quality := "-"
+	if item.Quality > 0 {
+		quality = fmt.Sprintf("%d", item.Quality)
+	}
+	return append(table, []string{slot, item.Name, fmt.Sprintf("%d", item.Count), quality})
+}
+
+func init() {
+	playerCmd.AddCommand(inventoryCmd)
+}
@@ -49,11 +49,13 @@ to list offline players (requires Alloc's Server Fixes Mod).`,
 		}
 		table := pterm.TableData{stdFields}
 
+		var players any
 		if !offline {
-			resp, err := Client.GetOnlinePlayers()
+			resp, err := Client.GetOnlinePlayersContext(cmd.Context())
 			if err != nil {
 				return err
 			}
+			players = resp.Data.Players
 			for idx := range resp.Data.Players {
 				player := &resp.Data.Players[idx]
 				table = append(table, []string{
@@ -68,11 +70,12 @@ to list offline players (requires Alloc's Server Fixes Mod).`,
 				})
 			}
 		} else {
-			resp, err := Client.GetAllPlayersM()
+			resp, err := Client.GetAllPlayersMContext(cmd.Context())
 			if err != nil {
 				CheckAllocsMissing(err)
 				return err
 			}
+			players = resp.Players
 
 			for idx := range resp.Players {
 				player := &resp.Players[idx]
@@ -89,9 +92,7 @@ to list offline players (requires Alloc's Server Fixes Mod).`,
 			}
 		}
 
-		pterm.DefaultTable.WithBoxed().WithHasHeader().WithData(table).Render()
-
-		return nil
+		return renderOutput(players, table)
 	},
 }
 
@@ -16,8 +16,13 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/prometheus/common/promlog"
@@ -28,6 +33,10 @@ import (
 
 const (
 	envNamespace = "SDTD"
+
+	// Output formats of listings.
+	outputTable = "table"
+	outputJSON  = "json"
 )
 
 var (
@@ -43,20 +52,44 @@ var rootCmd = &cobra.Command{
 		logger = promlog.New(&promlog.Config{})
 		var err error
 
-		Client, err = sdtdclient.NewSDTDClient(
+		if output := viper.GetString("output"); output != outputTable && output != outputJSON {
+			return fmt.Errorf("invalid output format %q, must be %s or %s", output, outputTable, outputJSON)
+		}
+
+		opts := []sdtdclient.Option{
+			sdtdclient.WithLogger(&logger),
+			sdtdclient.WithSSLVerify(viper.GetBool("ssl-verify")),
+			sdtdclient.WithTimeout(viper.GetDuration("timeout")),
+		}
+		if viper.GetBool("check-permissions") {
+			opts = append(opts, sdtdclient.WithPermissionChecks())
+		}
+		if caCert := viper.GetString("ca-cert"); caCert != "" {
+			opts = append(opts, sdtdclient.WithCACertFile(caCert))
+		}
+		if clientCert := viper.GetString("client-cert"); clientCert != "" {
+			opts = append(opts, sdtdclient.WithClientCertificate(clientCert, viper.GetString("client-key")))
+		}
+		if proxy := viper.GetString("proxy"); proxy != "" {
+			opts = append(opts, sdtdclient.WithProxy(proxy))
+		}
+		if userAgent := viper.GetString("user-agent"); userAgent != "" {
+			opts = append(opts, sdtdclient.WithUserAgent(userAgent))
+		}
+
+		Client, err = sdtdclient.NewSDTDClientWithOptions(
 			viper.GetString("host"),
 			&sdtdclient.SDTDAuth{
 				TokenName:   viper.GetString("token-name"),
 				TokenSecret: viper.GetString("token-secret"),
 			},
-			true,
-			&logger,
+			opts...,
 		)
 		if err != nil {
 			return err
 		}
 
-		err = Client.Connect()
+		err = Client.ConnectContext(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -66,7 +99,11 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
-	err := rootCmd.Execute()
+	// Cancel in-flight requests when the user interrupts the program.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -111,14 +148,77 @@ func init() {
 		fmt.Sprintf("The token secret to use [env: %s_TOKEN_SECRET]", envNamespace),
 	)
 
+	rootCmd.PersistentFlags().Bool(
+		"ssl-verify",
+		true,
+		fmt.Sprintf("Verify the server's TLS certificate [env: %s_SSL_VERIFY]", envNamespace),
+	)
+	rootCmd.PersistentFlags().String(
+		"ca-cert",
+		"",
+		fmt.Sprintf("PEM file of CA certificates used to verify the server [env: %s_CA_CERT]", envNamespace),
+	)
+	rootCmd.PersistentFlags().String(
+		"client-cert",
+		"",
+		fmt.Sprintf("PEM client certificate to present to the server [env: %s_CLIENT_CERT]", envNamespace),
+	)
+	rootCmd.PersistentFlags().String(
+		"client-key",
+		"",
+		fmt.Sprintf("PEM key of the client certificate [env: %s_CLIENT_KEY]", envNamespace),
+	)
+	rootCmd.PersistentFlags().String(
+		"proxy",
+		"",
+		fmt.Sprintf("URL of the proxy to send requests through [env: %s_PROXY]", envNamespace),
+	)
+	rootCmd.PersistentFlags().Duration(
+		"timeout",
+		30*time.Second,
+		fmt.Sprintf("Timeout of each request, 0 to disable [env: %s_TIMEOUT]", envNamespace),
+	)
+	rootCmd.PersistentFlags().String(
+		"user-agent",
+		"",
+		fmt.Sprintf("User-Agent header to send [env: %s_USER_AGENT]", envNamespace),
+	)
+
+	rootCmd.PersistentFlags().Bool(
+		"check-permissions",
+		true,
+		fmt.Sprintf("Reject commands the token lacks permission for before calling the API [env: %s_CHECK_PERMISSIONS]", envNamespace),
+	)
+
+	rootCmd.PersistentFlags().StringP(
+		"output",
+		"o",
+		outputTable,
+		fmt.Sprintf("Output format of listings, %s or %s [env: %s_OUTPUT]", outputTable, outputJSON, envNamespace),
+	)
+
 	rootCmd.MarkFlagRequired("host")
 	rootCmd.MarkFlagRequired("token-name")
 	rootCmd.MarkFlagRequired("token-secret")
 
 	viper.SetEnvPrefix(envNamespace)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv()
 
-	for _, name := range []string{"host", "token-name", "token-secret"} {
+	for _, name := range []string{
+		"host",
+		"token-name",
+		"token-secret",
+		"ssl-verify",
+		"ca-cert",
+		"client-cert",
+		"client-key",
+		"proxy",
+		"timeout",
+		"user-agent",
+		"check-permissions",
+		"output",
+	} {
 		if err := viper.BindPFlag(name, rootCmd.PersistentFlags().Lookup(name)); err != nil {
 			panic(err)
 		}
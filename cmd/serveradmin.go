@@ -0,0 +1,95 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// sayCmd represents the server say command
+var sayCmd = &cobra.Command{
+	Use:   "say <message...>",
+	Short: "Send a chat message to all players.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := Client.SayContext(cmd.Context(), strings.Join(args, " "))
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// saveworldCmd represents the server saveworld command
+var saveworldCmd = &cobra.Command{
+	Use:   "saveworld",
+	Short: "Save the world.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := Client.SaveWorldContext(cmd.Context())
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+// shutdownCmd represents the server shutdown command
+var shutdownCmd = &cobra.Command{
+	Use:   "shutdown",
+	Short: "Shut the server down.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !viper.GetBool("server.shutdown.yes") {
+			confirmed, err := pterm.DefaultInteractiveConfirm.Show("Shut the server down?")
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return nil
+			}
+		}
+
+		result, err := Client.ShutdownContext(cmd.Context())
+		if err != nil {
+			CheckAllocsMissing(err)
+			return err
+		}
+
+		fmt.Print(result.Result)
+		return nil
+	},
+}
+
+func init() {
+	serverCmd.AddCommand(sayCmd)
+	serverCmd.AddCommand(saveworldCmd)
+	serverCmd.AddCommand(shutdownCmd)
+
+	shutdownCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation.")
+	viper.BindPFlag("server.shutdown.yes", shutdownCmd.Flags().Lookup("yes"))
+}
@@ -17,9 +17,12 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var serverCmd = &cobra.Command{
@@ -33,7 +36,7 @@ var serverinfoCmd = &cobra.Command{
 	Short: "Return the server configuration.",
 	Long:  `Returns the contents of serverconfig.xml as a table of settings.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := Client.GetServerInfo()
+		resp, err := Client.GetServerInfoContext(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -46,9 +49,7 @@ var serverinfoCmd = &cobra.Command{
 			table = append(table, []string{setting.Name, setting.Type, fmt.Sprintf("%v", setting.Value)})
 		}
 
-		pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(table).Render()
-
-		return nil
+		return renderOutput(resp.Data, table)
 	},
 }
 
@@ -58,10 +59,13 @@ var serverstatsCmd = &cobra.Command{
 	Short: "Collect and return the server stats",
 	// Long: ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := Client.GetServerStats()
+		resp, err := Client.GetServerStatsContext(cmd.Context())
 		if err != nil {
 			return err
 		}
+		if viper.GetString("output") == outputJSON {
+			return renderOutput(resp.Data, nil)
+		}
 
 		gameTime := resp.Data.GameTime
 		table := pterm.TableData{
@@ -82,7 +86,7 @@ var serverprefsCmd = &cobra.Command{
 	Short: "Collect and return the game preferences",
 	// Long: ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		resp, err := Client.GetGamePrefs()
+		resp, err := Client.GetGamePrefsContext(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -100,6 +104,51 @@ var serverprefsCmd = &cobra.Command{
 			})
 		}
 
+		return renderOutput(resp.Data, table)
+	},
+}
+
+// servercapabilitiesCmd represents the server capabilities command
+var servercapabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Show what the server and token support",
+	Long: `Shows the server version, the web API modules available on the server
+along with the verbs the token is allowed to use on each, and the mod
+endpoints detected.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caps := Client.Capabilities()
+		if viper.GetString("output") == outputJSON {
+			return renderOutput(caps, nil)
+		}
+
+		modEndpoints := "-"
+		if len(caps.ModEndpoints) > 0 {
+			modEndpoints = strings.Join(caps.ModEndpoints, "\n")
+		}
+		table := pterm.TableData{
+			{"Version", caps.ServerVersion},
+			{"Build", caps.ServerBuild},
+			{"Alloc's Server Fixes", fmt.Sprintf("%v", caps.AllocsServerFixes)},
+			{"Mod Endpoints", modEndpoints},
+		}
+		pterm.DefaultTable.WithBoxed().WithData(table).Render()
+
+		modules := make([]string, 0, len(caps.Modules))
+		for module := range caps.Modules {
+			modules = append(modules, module)
+		}
+		slices.Sort(modules)
+		table = pterm.TableData{{"Module", "GET", "POST", "PUT", "DELETE"}}
+		for _, module := range modules {
+			verbs := caps.Modules[module]
+			table = append(table, []string{
+				module,
+				checkMark(verbs.Get),
+				checkMark(verbs.Post),
+				checkMark(verbs.Put),
+				checkMark(verbs.Delete),
+			})
+		}
 		pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(table).Render()
 
 		return nil
@@ -111,4 +160,5 @@ func init() {
 	serverCmd.AddCommand(serverinfoCmd)
 	serverCmd.AddCommand(serverstatsCmd)
 	serverCmd.AddCommand(serverprefsCmd)
+	serverCmd.AddCommand(servercapabilitiesCmd)
 }
@@ -16,10 +16,15 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
 	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
 )
 
@@ -33,7 +38,53 @@ func CheckAllocsMissing(err error) {
 			"This command requires Alloc's Server Fixes to be installed on the server.",
 		)
 		os.Exit(1)
-	} else {
-		fmt.Println("nope")
 	}
 }
+
+// Returns a check mark if allowed is true, or a dash otherwise.
+func checkMark(allowed bool) string {
+	if allowed {
+		return "✓"
+	}
+	return "-"
+}
+
+// Parses x, y and z coordinate arguments into a location.
+func parseLocation(args []string) (sdtdclient.Location, error) {
+	var coords [3]int
+	for idx := range coords {
+		value, err := strconv.Atoi(args[idx])
+		if err != nil {
+			return sdtdclient.Location{}, fmt.Errorf("invalid coordinate %q: %w", args[idx], err)
+		}
+		coords[idx] = value
+	}
+	return sdtdclient.Location{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
+
+// Parses world coordinates given as "x,z" or "x,y,z". hasY reports whether the
+// height was given; it is 0 otherwise.
+func parseCoords(s string) (location sdtdclient.Location, hasY bool, err error) {
+	coords := strings.Split(s, ",")
+	switch len(coords) {
+	case 2:
+		location, err = parseLocation([]string{coords[0], "0", coords[1]})
+	case 3:
+		location, err = parseLocation(coords)
+		hasY = true
+	default:
+		err = fmt.Errorf(`invalid coordinates %q, expected "x,z" or "x,y,z"`, s)
+	}
+	return location, hasY, err
+}
+
+// Prints a listing in the output format selected with --output: the given
+// table, or value encoded as JSON.
+func renderOutput(value any, table pterm.TableData) error {
+	if viper.GetString("output") == outputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	return pterm.DefaultTable.WithBoxed().WithHasHeader().WithData(table).Render()
+}
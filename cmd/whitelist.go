@@ -18,6 +18,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +38,34 @@ var whitelistCmd = &cobra.Command{
 	//	},
 }
 
+// whitelistListCmd represents the whitelist list command
+var whitelistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the whitelisted users and groups.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		whitelist, err := Client.GetWhitelistContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := pterm.TableData{{"Type", "Name", "ID", "Permission Level"}}
+		for _, user := range whitelist.Data.Users {
+			table = append(table, []string{"User", user.Name, user.UserID, ""})
+		}
+		for _, group := range whitelist.Data.Groups {
+			table = append(table, []string{
+				"Group",
+				group.Name,
+				group.GroupID,
+				fmt.Sprintf("%v", group.PermissionLevel),
+			})
+		}
+
+		return renderOutput(whitelist.Data, table)
+	},
+}
+
 // adduserCmd represents the adduser command
 var adduserCmd = &cobra.Command{
 	Use:   "adduser <name> <id>",
@@ -44,7 +73,7 @@ var adduserCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name, id := args[0], args[1]
-		err := Client.AddWhitelistUser(id, name)
+		err := Client.AddWhitelistUserContext(cmd.Context(), id, name)
 		if err != nil {
 			return err
 		}
@@ -56,11 +85,11 @@ var adduserCmd = &cobra.Command{
 
 // deleteuserCmd represents the deleteuser command
 var deleteuserCmd = &cobra.Command{
-	Use:   "deleteuser <name> <id>",
+	Use:   "deleteuser <id>",
 	Short: "Delete a user from the whitelist.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := Client.DeleteWhitelistUser(args[0])
+		err := Client.DeleteWhitelistUserContext(cmd.Context(), args[0])
 		if err != nil {
 			return err
 		}
@@ -70,10 +99,46 @@ var deleteuserCmd = &cobra.Command{
 	},
 }
 
+// addgroupCmd represents the addgroup command
+var addgroupCmd = &cobra.Command{
+	Use:   "addgroup <name> <id>",
+	Short: "Add a Steam group to the whitelist.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, id := args[0], args[1]
+		err := Client.AddWhitelistGroupContext(cmd.Context(), id, name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added group '%s' (%v) to the whitelist.\n", name, id)
+		return nil
+	},
+}
+
+// deletegroupCmd represents the deletegroup command
+var deletegroupCmd = &cobra.Command{
+	Use:   "deletegroup <id>",
+	Short: "Delete a Steam group from the whitelist.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := Client.DeleteWhitelistGroupContext(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted group %v from the whitelist.\n", args[0])
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(whitelistCmd)
+	whitelistCmd.AddCommand(whitelistListCmd)
 	whitelistCmd.AddCommand(adduserCmd)
 	whitelistCmd.AddCommand(deleteuserCmd)
+	whitelistCmd.AddCommand(addgroupCmd)
+	whitelistCmd.AddCommand(deletegroupCmd)
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,218 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+	"gopkg.in/yaml.v3"
+)
+
+// An entry of a whitelist roster file.
+type rosterEntry struct {
+	Name string `yaml:"name"`
+	ID   string `yaml:"id"`
+}
+
+// A whitelist roster file.
+type roster struct {
+	Users  []rosterEntry `yaml:"users"`
+	Groups []rosterEntry `yaml:"groups"`
+}
+
+// applyCmd represents the whitelist apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <roster>",
+	Short: "Make the whitelist match a roster file.",
+	Long: `Make the whitelist match a roster file. Users and groups in the roster
+that are missing from the whitelist, or listed under another name, are added.
+With --prune, whitelist entries missing from the roster are removed as well.
+
+The roster is either a YAML file:
+
+  users:
+    - name: bob
+      id: Steam_76561198000000000
+  groups:
+    - name: my clan
+      id: "103582791400000000"
+
+or a CSV file (with a .csv extension) with type, name and id columns, where
+type is "user" or "group":
+
+  type,name,id
+  user,bob,Steam_76561198000000000
+  group,my clan,103582791400000000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		desired, err := loadRoster(viper.GetString("whitelist.apply.file"))
+		if err != nil {
+			return err
+		}
+
+		current, err := Client.GetWhitelistContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		plan := sdtdclient.PlanWhitelist(&current.Data, desired, viper.GetBool("whitelist.apply.prune"))
+		if plan.Empty() {
+			fmt.Println("The whitelist is up to date.")
+			return nil
+		}
+
+		printWhitelistPlan(plan)
+		if viper.GetBool("whitelist.apply.dry-run") {
+			return nil
+		}
+
+		if !viper.GetBool("whitelist.apply.yes") {
+			confirmed, err := pterm.DefaultInteractiveConfirm.Show("Apply these changes?")
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return nil
+			}
+		}
+
+		if err := Client.ApplyWhitelistPlanContext(cmd.Context(), plan); err != nil {
+			return err
+		}
+		fmt.Println("Applied the changes to the whitelist.")
+		return nil
+	},
+}
+
+// Print the changes of a whitelist plan as a table.
+func printWhitelistPlan(plan *sdtdclient.WhitelistPlan) {
+	table := pterm.TableData{{"Action", "Type", "Name", "ID"}}
+	for _, user := range plan.AddUsers {
+		table = append(table, []string{"Add", "User", user.Name, user.UserID})
+	}
+	for _, group := range plan.AddGroups {
+		table = append(table, []string{"Add", "Group", group.Name, group.GroupID})
+	}
+	for _, user := range plan.RemoveUsers {
+		table = append(table, []string{"Remove", "User", user.Name, user.UserID})
+	}
+	for _, group := range plan.RemoveGroups {
+		table = append(table, []string{"Remove", "Group", group.Name, group.GroupID})
+	}
+	pterm.DefaultTable.WithBoxed().WithHasHeader().WithData(table).Render()
+}
+
+// Load a roster file, in CSV format if it has a .csv extension and YAML
+// otherwise.
+func loadRoster(path string) (*sdtdclient.WhitelistData, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no roster file given, use --file")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries *roster
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = readRosterCSV(file)
+	} else {
+		entries = &roster{}
+		err = yaml.NewDecoder(file).Decode(entries)
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading roster %s: %w", path, err)
+	}
+
+	whitelist := &sdtdclient.WhitelistData{}
+	seen := map[string]bool{}
+	for _, entry := range entries.Users {
+		if entry.ID == "" || seen["user "+entry.ID] {
+			return nil, fmt.Errorf("roster %s: missing or duplicate user id %q", path, entry.ID)
+		}
+		seen["user "+entry.ID] = true
+		whitelist.Users = append(whitelist.Users, sdtdclient.WhitelistUser{Name: entry.Name, UserID: entry.ID})
+	}
+	for _, entry := range entries.Groups {
+		if entry.ID == "" || seen["group "+entry.ID] {
+			return nil, fmt.Errorf("roster %s: missing or duplicate group id %q", path, entry.ID)
+		}
+		seen["group "+entry.ID] = true
+		whitelist.Groups = append(whitelist.Groups, sdtdclient.WhitelistGroup{Name: entry.Name, GroupID: entry.ID})
+	}
+	return whitelist, nil
+}
+
+// Read a CSV roster with type, name and id columns and an optional header.
+func readRosterCSV(r io.Reader) (*roster, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := &roster{}
+	for idx, record := range records {
+		entry := rosterEntry{Name: record[1], ID: record[2]}
+		switch strings.ToLower(record[0]) {
+		case "user":
+			entries.Users = append(entries.Users, entry)
+		case "group":
+			entries.Groups = append(entries.Groups, entry)
+		case "type":
+			if idx == 0 {
+				continue
+			}
+			fallthrough
+		default:
+			return nil, fmt.Errorf("line %d: unknown entry type %q", idx+1, record[0])
+		}
+	}
+	return entries, nil
+}
+
+func init() {
+	whitelistCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringP("file", "f", "", "The roster file to apply (YAML or CSV).")
+	applyCmd.Flags().Bool("dry-run", false, "Only print the changes, do not apply them.")
+	applyCmd.Flags().Bool("prune", false, "Remove whitelist entries missing from the roster.")
+	applyCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation.")
+	applyCmd.MarkFlagRequired("file")
+
+	viper.BindPFlag("whitelist.apply.file", applyCmd.Flags().Lookup("file"))
+	viper.BindPFlag("whitelist.apply.dry-run", applyCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("whitelist.apply.prune", applyCmd.Flags().Lookup("prune"))
+	viper.BindPFlag("whitelist.apply.yes", applyCmd.Flags().Lookup("yes"))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 Tom Helander <thomas.helander@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// whoamiCmd represents the whoami command
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the token's permission level and module permissions.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resp, err := Client.GetUserStatusContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if viper.GetString("output") == outputJSON {
+			return renderOutput(resp.Data, nil)
+		}
+
+		table := pterm.TableData{
+			{"Token", Client.Auth.TokenName},
+			{"Permission Level", fmt.Sprintf("%d", resp.Data.PermissionLevel)},
+		}
+		pterm.DefaultTable.WithBoxed().WithData(table).Render()
+
+		permissions := slices.Clone(resp.Data.Permissions)
+		slices.SortFunc(permissions, func(a, b sdtdclient.Permission) int {
+			return strings.Compare(a.Module, b.Module)
+		})
+
+		table = pterm.TableData{{"Module", "GET", "POST", "PUT", "DELETE"}}
+		for idx := range permissions {
+			permission := &permissions[idx]
+			table = append(table, []string{
+				permission.Module,
+				checkMark(permission.Allowed.Get),
+				checkMark(permission.Allowed.Post),
+				checkMark(permission.Allowed.Put),
+				checkMark(permission.Allowed.Delete),
+			})
+		}
+		pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(table).Render()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consoletext parses console command output needed by both the client,
+// for its console fallbacks, and the parser package. It does not depend on the
+// client so that the client can use it.
+package consoletext
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A line of output that cannot be parsed.
+type LineError struct {
+	Line   int    // Line number, starting at 1
+	Text   string // The line that failed to parse
+	Reason string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("malformed line %d: %s: %q", e.Line, e.Reason, e.Text)
+}
+
+// A user or Steam group listed by "admin list".
+type Permission struct {
+	ID           string
+	Name         string
+	Level        int
+	OfficerLevel int // Level of group officers, equal to Level for users
+}
+
+// The admins listed by "admin list".
+type AdminList struct {
+	Users  []Permission
+	Groups []Permission
+}
+
+// e.g. "  0: Steam_76561198000000000 (Steve)" or, for groups,
+// "  0/1: 103582791429521412 (Group)"
+var permissionRe = regexp.MustCompile(`^\s*(-?\d+)(?:\s*/\s*(-?\d+))?:\s+(\S+)(?:\s+\((.*)\))?\s*$`)
+
+// Column headers printed before the entries of "admin list" and "whitelist
+// list", e.g. "  Level: UserID (Player name if online, stored name)".
+var columnHeaders = []string{"Level", "UserID", "SteamGroupID"}
+
+// Split output into lines, dropping carriage returns.
+func Lines(output string) []string {
+	return strings.Split(strings.ReplaceAll(output, "\r", ""), "\n")
+}
+
+// Reports whether a trimmed line is a column header.
+func IsColumnHeader(trimmed string) bool {
+	for _, header := range columnHeaders {
+		if strings.HasPrefix(trimmed, header+" ") || strings.HasPrefix(trimmed, header+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether a column header announces entries naming a user by their
+// name if online and their stored name, e.g. "(Steve, Steve)".
+func HasOnlineName(header string) bool {
+	return strings.Contains(header, "if online")
+}
+
+// Returns the stored name from an "online name, stored name" pair, or the
+// online name if no name is stored.
+func StoredName(names string) string {
+	half := (len(names) - 2) / 2
+	if len(names)%2 == 0 && half >= 0 && names[half:half+2] == ", " && names[:half] == names[half+2:] {
+		return names[:half]
+	}
+	online, stored, ok := cutLast(names, ", ")
+	if !ok {
+		return names
+	}
+	if stored == "" {
+		return online
+	}
+	return stored
+}
+
+// Slice s around the last instance of sep.
+func cutLast(s string, sep string) (before string, after string, found bool) {
+	if idx := strings.LastIndex(s, sep); idx >= 0 {
+		return s[:idx], s[idx+len(sep):], true
+	}
+	return s, "", false
+}
+
+// Parse the output of "admin list". Column headers are skipped. Lines that
+// cannot be parsed cause a *LineError.
+func ParseAdminList(output string) (*AdminList, error) {
+	admins := &AdminList{Users: []Permission{}, Groups: []Permission{}}
+
+	section, onlineNames := "", false
+	for idx, line := range Lines(output) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasSuffix(trimmed, ":") {
+			section, onlineNames = strings.ToLower(trimmed), false
+			continue
+		}
+		if IsColumnHeader(trimmed) {
+			onlineNames = HasOnlineName(trimmed)
+			continue
+		}
+		if trimmed == "" || section == "" {
+			continue
+		}
+
+		m := permissionRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &LineError{idx + 1, line, "not a permission entry"}
+		}
+		permission := Permission{ID: m[3], Name: m[4]}
+		permission.Level, _ = strconv.Atoi(m[1])
+		permission.OfficerLevel = permission.Level
+		if m[2] != "" {
+			permission.OfficerLevel, _ = strconv.Atoi(m[2])
+		}
+
+		switch {
+		case strings.Contains(section, "group"):
+			admins.Groups = append(admins.Groups, permission)
+		case strings.Contains(section, "user"):
+			if onlineNames {
+				permission.Name = StoredName(permission.Name)
+			}
+			admins.Users = append(admins.Users, permission)
+		}
+	}
+	return admins, nil
+}
@@ -0,0 +1,386 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package parser turns the text output of 7 Days to Die console commands into
+// typed values.
+//
+// Lines that do not belong to the command's output (blank lines, summaries,
+// interleaved log lines) are skipped. Lines that look like entries but cannot
+// be parsed cause a *LineError.
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thelande/sdtd_client/internal/consoletext"
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+var ErrMalformedLine = errors.New("malformed line")
+
+// Error returned when a line of output cannot be parsed. Matches
+// ErrMalformedLine with errors.Is.
+type LineError struct {
+	Line   int    // Line number, starting at 1
+	Text   string // The line that failed to parse
+	Reason string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("%s %d: %s: %q", ErrMalformedLine, e.Line, e.Reason, e.Text)
+}
+
+func (e *LineError) Unwrap() error {
+	return ErrMalformedLine
+}
+
+// An entity reported by listents.
+type Entity struct {
+	ID       int
+	Type     string // Entity class, e.g. "EntityZombie"
+	Name     string
+	Position sdtdclient.Location
+	Rotation sdtdclient.Location
+	Lifetime string // Remaining lifetime, "float.Max" for permanent entities
+	Remote   bool
+	Dead     bool
+	Health   int
+}
+
+var (
+	entryRe = regexp.MustCompile(`^\s*\d+\.\s+id=`)
+
+	playerRe = regexp.MustCompile(
+		`^\s*\d+\.\s+id=(\d+),\s+(.*?),\s+pos=\(([^)]*)\),\s+rot=\(([^)]*)\)(?:,\s+(.*))?$`,
+	)
+	playerIDRe = regexp.MustCompile(`^\s*\d+\.\s+id=(\d+),\s+(.*?)(?:,\s+(\w+=.*))?$`)
+	entityRe   = regexp.MustCompile(
+		`^\s*\d+\.\s+id=(\d+),\s+\[type=(\w+),\s+name=(.*?),\s+id=\d+\],\s+pos=\(([^)]*)\),\s+rot=\(([^)]*)\)(?:,\s+(.*))?$`,
+	)
+	// e.g. "  2024-12-31 23:59:00 - Steam_76561198000000000 (Steve) - griefing"
+	banRe = regexp.MustCompile(`^\s*(\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}(?::\d{2})?)\s+-\s+(\S+)(?:\s+\((.*?)\))?(?:\s+-\s+(.*))?$`)
+	// e.g. "  Steam_76561198000000000 (Steve)"
+	idNameRe   = regexp.MustCompile(`^\s+(\S+)(?:\s+\((.*)\))?\s*$`)
+	gamePrefRe = regexp.MustCompile(`^\s*GamePref\.(\w+)\s*=\s?(.*)$`)
+)
+
+// Parse comma separated key=value pairs.
+func parseKeyValues(s string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// Parse a "x, y, z" vector, flooring each component to a block coordinate.
+func parseVector(s string) (sdtdclient.Location, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return sdtdclient.Location{}, fmt.Errorf("expected 3 components, got %d", len(parts))
+	}
+
+	var coords [3]int
+	for idx, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return sdtdclient.Location{}, err
+		}
+		coords[idx] = int(math.Floor(value))
+	}
+	return sdtdclient.Location{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
+
+// Parse an integer value, treating a missing value as 0.
+func atoi(values map[string]string, key string) (int, error) {
+	value, ok := values[key]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// Parse the output of listplayers (lp).
+func ParseListPlayers(output string) ([]sdtdclient.Player, error) {
+	players := []sdtdclient.Player{}
+	for idx, line := range consoletext.Lines(output) {
+		if !entryRe.MatchString(line) {
+			continue
+		}
+
+		m := playerRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &LineError{idx + 1, line, "not a player entry"}
+		}
+
+		player := sdtdclient.Player{Name: m[2], Online: true}
+		var err error
+		if player.EntityID, err = strconv.Atoi(m[1]); err != nil {
+			return nil, &LineError{idx + 1, line, err.Error()}
+		}
+		if player.Position, err = parseVector(m[3]); err != nil {
+			return nil, &LineError{idx + 1, line, "pos: " + err.Error()}
+		}
+
+		values := parseKeyValues(m[5])
+		player.PlatformID = values["pltfmid"]
+		if player.PlatformID == "" {
+			player.PlatformID = values["steamid"]
+		}
+		player.CrossPlatformID = values["crossid"]
+		player.IP = values["ip"]
+
+		for key, field := range map[string]*int{
+			"health":  &player.Health,
+			"deaths":  &player.Deaths,
+			"zombies": &player.Kills.Zombies,
+			"players": &player.Kills.Players,
+			"score":   &player.Score,
+			"level":   &player.Level,
+			"ping":    &player.Ping,
+		} {
+			if *field, err = atoi(values, key); err != nil {
+				return nil, &LineError{idx + 1, line, key + ": " + err.Error()}
+			}
+		}
+
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+// Parse the output of listplayerids (lpi). Only the entity ID, name, platform
+// IDs and IP of the returned players are set.
+func ParseListPlayerIDs(output string) ([]sdtdclient.Player, error) {
+	players := []sdtdclient.Player{}
+	for idx, line := range consoletext.Lines(output) {
+		if !entryRe.MatchString(line) {
+			continue
+		}
+
+		m := playerIDRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &LineError{idx + 1, line, "not a player entry"}
+		}
+
+		id, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, &LineError{idx + 1, line, err.Error()}
+		}
+
+		values := parseKeyValues(m[3])
+		player := sdtdclient.Player{
+			EntityID:        id,
+			Name:            m[2],
+			PlatformID:      values["pltfmid"],
+			CrossPlatformID: values["crossid"],
+			IP:              values["ip"],
+		}
+		if player.PlatformID == "" {
+			player.PlatformID = values["steamid"]
+		}
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+// Parse the output of listents (le).
+func ParseListEntities(output string) ([]Entity, error) {
+	entities := []Entity{}
+	for idx, line := range consoletext.Lines(output) {
+		if !entryRe.MatchString(line) {
+			continue
+		}
+
+		m := entityRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &LineError{idx + 1, line, "not an entity entry"}
+		}
+
+		entity := Entity{Type: m[2], Name: m[3]}
+		var err error
+		if entity.ID, err = strconv.Atoi(m[1]); err != nil {
+			return nil, &LineError{idx + 1, line, err.Error()}
+		}
+		if entity.Position, err = parseVector(m[4]); err != nil {
+			return nil, &LineError{idx + 1, line, "pos: " + err.Error()}
+		}
+		if entity.Rotation, err = parseVector(m[5]); err != nil {
+			return nil, &LineError{idx + 1, line, "rot: " + err.Error()}
+		}
+
+		values := parseKeyValues(m[6])
+		entity.Lifetime = values["lifetime"]
+		entity.Remote = strings.EqualFold(values["remote"], "true")
+		entity.Dead = strings.EqualFold(values["dead"], "true")
+		if entity.Health, err = atoi(values, "health"); err != nil {
+			return nil, &LineError{idx + 1, line, "health: " + err.Error()}
+		}
+
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// Parse the output of listitems (li), returning the item names.
+func ParseListItems(output string) ([]string, error) {
+	items := []string{}
+	for _, line := range consoletext.Lines(output) {
+		item := strings.TrimSpace(line)
+		if item == "" || strings.HasPrefix(item, "Listed ") || strings.ContainsAny(item, " \t") {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Parse the output of "admin list". Column headers are skipped.
+func ParseAdminList(output string) (*sdtdclient.AdminsData, error) {
+	list, err := consoletext.ParseAdminList(output)
+	var lineErr *consoletext.LineError
+	if errors.As(err, &lineErr) {
+		return nil, &LineError{lineErr.Line, lineErr.Text, lineErr.Reason}
+	} else if err != nil {
+		return nil, err
+	}
+
+	admins := &sdtdclient.AdminsData{
+		Users:  make([]sdtdclient.AdminUser, 0, len(list.Users)),
+		Groups: make([]sdtdclient.AdminGroup, 0, len(list.Groups)),
+	}
+	for _, user := range list.Users {
+		admins.Users = append(admins.Users, sdtdclient.AdminUser{
+			UserID:          user.ID,
+			Name:            user.Name,
+			PermissionLevel: user.Level,
+		})
+	}
+	for _, group := range list.Groups {
+		admins.Groups = append(admins.Groups, sdtdclient.AdminGroup{
+			GroupID:               group.ID,
+			Name:                  group.Name,
+			PermissionLevelNormal: group.Level,
+			PermissionLevelMods:   group.OfficerLevel,
+		})
+	}
+	return admins, nil
+}
+
+// Parse the output of "ban list". Expiry times are taken to be in UTC.
+func ParseBanList(output string) ([]sdtdclient.BanEntry, error) {
+	bans := []sdtdclient.BanEntry{}
+	for idx, line := range consoletext.Lines(output) {
+		m := banRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		until, err := sdtdclient.ParseTimestamp(m[1])
+		if err != nil {
+			return nil, &LineError{idx + 1, line, err.Error()}
+		}
+		bans = append(bans, sdtdclient.BanEntry{
+			Name:   m[3],
+			UserID: m[2],
+			Until:  until,
+			Reason: m[4],
+		})
+	}
+	return bans, nil
+}
+
+// Parse the output of "whitelist list". Column headers are skipped.
+func ParseWhitelist(output string) (*sdtdclient.WhitelistData, error) {
+	whitelist := &sdtdclient.WhitelistData{
+		Users:  []sdtdclient.WhitelistUser{},
+		Groups: []sdtdclient.WhitelistGroup{},
+	}
+
+	section, onlineNames := "", false
+	for idx, line := range consoletext.Lines(output) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasSuffix(trimmed, ":") {
+			section, onlineNames = strings.ToLower(trimmed), false
+			continue
+		}
+		if consoletext.IsColumnHeader(trimmed) {
+			onlineNames = consoletext.HasOnlineName(trimmed)
+			continue
+		}
+		if trimmed == "" || section == "" || !strings.Contains(section, "whitelist") {
+			continue
+		}
+
+		m := idNameRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &LineError{idx + 1, line, "not a whitelist entry"}
+		}
+
+		switch {
+		case strings.Contains(section, "group"):
+			whitelist.Groups = append(whitelist.Groups, sdtdclient.WhitelistGroup{GroupID: m[1], Name: m[2]})
+		case strings.Contains(section, "user"):
+			name := m[2]
+			if onlineNames {
+				name = consoletext.StoredName(name)
+			}
+			whitelist.Users = append(whitelist.Users, sdtdclient.WhitelistUser{UserID: m[1], Name: name})
+		}
+	}
+	return whitelist, nil
+}
+
+// Parse the output of getgamepref (gg). Values are converted to int, float64
+// or bool when possible and Type is set to "int", "float", "bool" or "string"
+// accordingly.
+func ParseGamePrefs(output string) ([]sdtdclient.GamePrefData, error) {
+	prefs := []sdtdclient.GamePrefData{}
+	for _, line := range consoletext.Lines(output) {
+		m := gamePrefRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		pref := sdtdclient.GamePrefData{}
+		pref.Name = m[1]
+		pref.Type, pref.Value = typedValue(m[2])
+		prefs = append(prefs, pref)
+	}
+	return prefs, nil
+}
+
+// Convert a game preference value to its most specific type.
+func typedValue(s string) (string, any) {
+	if value, err := strconv.Atoi(s); err == nil {
+		return "int", value
+	}
+	if value, err := strconv.ParseFloat(s, 64); err == nil {
+		return "float", value
+	}
+	if value, err := strconv.ParseBool(s); err == nil && (strings.EqualFold(s, "true") || strings.EqualFold(s, "false")) {
+		return "bool", value
+	}
+	return "string", s
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// Wraps a parser to return its result as any.
+func parseAs[T any](parse func(string) (T, error)) func(string) (any, error) {
+	return func(output string) (any, error) {
+		return parse(output)
+	}
+}
+
+// Each parser is run on testdata/<name>.txt, the output of the console command,
+// and its result compared to testdata/<name>.golden, encoded as JSON. Run the
+// tests with -update to write the golden files.
+//
+// The transcripts in testdata are written by hand from the output formats of
+// the game and should be replaced by output captured from a server, e.g. with
+// "sdtd_client command run lp", with the names, IDs and IPs scrubbed.
+func TestParsers(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		parse func(string) (any, error)
+	}{
+		{"listplayers", parseAs(ParseListPlayers)},
+		{"listplayerids", parseAs(ParseListPlayerIDs)},
+		{"listents", parseAs(ParseListEntities)},
+		{"listitems", parseAs(ParseListItems)},
+		{"adminlist", parseAs(ParseAdminList)},
+		{"banlist", parseAs(ParseBanList)},
+		{"whitelist", parseAs(ParseWhitelist)},
+		{"getgamepref", parseAs(ParseGamePrefs)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := os.ReadFile(filepath.Join("testdata", test.name+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := test.parse(string(output))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			got, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", test.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("result differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+// Entries whose names or reasons contain the separators of the output.
+func TestSeparatorsInValues(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		parse  func(string) (string, error)
+		output string
+		want   string
+	}{
+		{
+			"player name with a comma",
+			func(output string) (string, error) {
+				players, err := ParseListPlayers(output)
+				if err != nil || len(players) != 1 {
+					return "", err
+				}
+				return players[0].Name, nil
+			},
+			"0. id=2045, Alice, Jr., pos=(12.0, 48.9, -3.2), rot=(0.0, 270.0, 0.0), remote=True, health=58, pltfmid=XBL_2535412345678901, ping=121\n",
+			"Alice, Jr.",
+		},
+		{
+			"admin online and stored name differ",
+			func(output string) (string, error) {
+				admins, err := ParseAdminList(output)
+				if err != nil || len(admins.Users) != 1 {
+					return "", err
+				}
+				return admins.Users[0].Name, nil
+			},
+			"Defined User Permissions:\n  Level: UserID (Player name if online, stored name)\n      0: Steam_1 (Bobby, Bob)\n",
+			"Bob",
+		},
+		{
+			"group name with a comma",
+			func(output string) (string, error) {
+				whitelist, err := ParseWhitelist(output)
+				if err != nil || len(whitelist.Groups) != 1 {
+					return "", err
+				}
+				return whitelist.Groups[0].Name, nil
+			},
+			"Whitelisted groups:\n  SteamGroupID (Stored name)\n  103582791429521412 (Moderators, Inc.)\n",
+			"Moderators, Inc.",
+		},
+		{
+			"ban reason with a dash",
+			func(output string) (string, error) {
+				bans, err := ParseBanList(output)
+				if err != nil || len(bans) != 1 {
+					return "", err
+				}
+				return bans[0].Reason, nil
+			},
+			"Ban list entries:\n  2099-01-01 00:00:00 - XBL_1 - cheating - repeat offender\n",
+			"cheating - repeat offender",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.parse(test.output)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMalformedLines(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		parse  func(string) (any, error)
+		output string
+		line   int
+	}{
+		{"listplayers", parseAs(ParseListPlayers), "0. id=171, Steve\nTotal of 1 in the game\n", 1},
+		{"listents", parseAs(ParseListEntities), "1. id=171, [type=EntityPlayer, name=Steve, id=171], pos=(1, 2), rot=(0, 0, 0)\n", 1},
+		{"adminlist", parseAs(ParseAdminList), "Defined User Permissions:\n  admin: Steam_1 (Steve)\n", 2},
+		{"whitelist", parseAs(ParseWhitelist), "Whitelisted users:\nSteam_1 (Steve)\n", 2},
+		{"banlist", parseAs(ParseBanList), "Ban list entries:\n  2024-13-45 10:00:00 - Steam_1 (Steve)\n", 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.parse(test.output)
+			var lineErr *LineError
+			if !errors.As(err, &lineErr) || !errors.Is(err, ErrMalformedLine) {
+				t.Fatalf("got error %v, want a *LineError", err)
+			}
+			if lineErr.Line != test.line {
+				t.Errorf("got line %d, want %d", lineErr.Line, test.line)
+			}
+		})
+	}
+}
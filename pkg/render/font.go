@@ -0,0 +1,129 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package render
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+// A tiny 3x5 pixel bitmap font covering upper case letters, digits and common
+// punctuation, used to label markers on PNG images. Lower case letters are
+// drawn in upper case and other characters as a question mark.
+const (
+	glyphWidth   = 3
+	glyphHeight  = 5
+	glyphSpacing = 1
+)
+
+var glyphs = map[rune][glyphHeight]string{
+	'A':  {".#.", "#.#", "###", "#.#", "#.#"},
+	'B':  {"##.", "#.#", "##.", "#.#", "##."},
+	'C':  {".##", "#..", "#..", "#..", ".##"},
+	'D':  {"##.", "#.#", "#.#", "#.#", "##."},
+	'E':  {"###", "#..", "##.", "#..", "###"},
+	'F':  {"###", "#..", "##.", "#..", "#.."},
+	'G':  {".##", "#..", "#.#", "#.#", ".##"},
+	'H':  {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I':  {"###", ".#.", ".#.", ".#.", "###"},
+	'J':  {"..#", "..#", "..#", "#.#", ".#."},
+	'K':  {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L':  {"#..", "#..", "#..", "#..", "###"},
+	'M':  {"#.#", "###", "###", "#.#", "#.#"},
+	'N':  {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O':  {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P':  {"##.", "#.#", "##.", "#..", "#.."},
+	'Q':  {".#.", "#.#", "#.#", "##.", ".##"},
+	'R':  {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S':  {".##", "#..", ".#.", "..#", "##."},
+	'T':  {"###", ".#.", ".#.", ".#.", ".#."},
+	'U':  {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V':  {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W':  {"#.#", "#.#", "###", "###", "#.#"},
+	'X':  {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y':  {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z':  {"###", "..#", ".#.", "#..", "###"},
+	'0':  {"###", "#.#", "#.#", "#.#", "###"},
+	'1':  {".#.", "##.", ".#.", ".#.", "###"},
+	'2':  {"##.", "..#", ".#.", "#..", "###"},
+	'3':  {"##.", "..#", ".#.", "..#", "##."},
+	'4':  {"#.#", "#.#", "###", "..#", "..#"},
+	'5':  {"###", "#..", "##.", "..#", "##."},
+	'6':  {".##", "#..", "###", "#.#", "###"},
+	'7':  {"###", "..#", ".#.", ".#.", ".#."},
+	'8':  {"###", "#.#", "###", "#.#", "###"},
+	'9':  {"###", "#.#", "###", "..#", "##."},
+	' ':  {"...", "...", "...", "...", "..."},
+	'.':  {"...", "...", "...", "...", ".#."},
+	',':  {"...", "...", "...", ".#.", "#.."},
+	':':  {"...", ".#.", "...", ".#.", "..."},
+	'-':  {"...", "...", "###", "...", "..."},
+	'_':  {"...", "...", "...", "...", "###"},
+	'+':  {"...", ".#.", "###", ".#.", "..."},
+	'/':  {"..#", "..#", ".#.", "#..", "#.."},
+	'(':  {".#.", "#..", "#..", "#..", ".#."},
+	')':  {".#.", "..#", "..#", "..#", ".#."},
+	'[':  {"##.", "#..", "#..", "#..", "##."},
+	']':  {".##", "..#", "..#", "..#", ".##"},
+	'\'': {".#.", ".#.", "...", "...", "..."},
+	'!':  {".#.", ".#.", ".#.", "...", ".#."},
+	'?':  {"##.", "..#", ".#.", "...", ".#."},
+	'#':  {"#.#", "###", "#.#", "###", "#.#"},
+	'*':  {"...", "#.#", ".#.", "#.#", "..."},
+}
+
+// Returns the glyph drawn for a character.
+func glyphFor(r rune) [glyphHeight]string {
+	if glyph, ok := glyphs[unicode.ToUpper(r)]; ok {
+		return glyph
+	}
+	return glyphs['?']
+}
+
+// Returns the size in pixels of a text drawn at the given scale.
+func textSize(text string, scale int) image.Point {
+	chars := len([]rune(text))
+	if chars == 0 {
+		return image.Point{}
+	}
+	return image.Point{
+		X: (chars*(glyphWidth+glyphSpacing) - glyphSpacing) * scale,
+		Y: glyphHeight * scale,
+	}
+}
+
+// Draw a text with its top left corner at the given point.
+func drawText(img *image.RGBA, at image.Point, text string, scale int, c color.Color) {
+	x := at.X
+	for _, r := range text {
+		glyph := glyphFor(r)
+		for row, line := range glyph {
+			for col, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				fillRect(img, image.Rect(
+					x+col*scale,
+					at.Y+row*scale,
+					x+(col+1)*scale,
+					at.Y+(row+1)*scale,
+				), c)
+			}
+		}
+		x += (glyphWidth + glyphSpacing) * scale
+	}
+}
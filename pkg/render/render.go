@@ -0,0 +1,288 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render draws labelled overlays of players, hostiles and land claims
+// onto images of the web map, as PNG or SVG.
+//
+// World locations are placed on the image using the tile bounds the image
+// covers (see sdtdclient.TileBounds), so overlays line up with stitched map
+// tiles. Without tiles, a blank grid with one cell per tile is drawn instead.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// The kind of a feature drawn on the map.
+type Kind int
+
+const (
+	Player Kind = iota
+	HostileCluster
+	LandClaim
+)
+
+// A feature drawn on top of the map.
+type Feature struct {
+	Kind     Kind
+	Position sdtdclient.Location
+	Label    string
+	Size     int  // Number of hostiles in a cluster, or width of a land claim in blocks
+	Inactive bool // Land claims no longer protecting the land
+}
+
+// A group of entities close to each other.
+type Cluster struct {
+	Center sdtdclient.Location // Average location of the entities
+	Count  int
+}
+
+// A map image with overlays.
+type Map struct {
+	Bounds     sdtdclient.TileBounds // The tiles covered by the image
+	Base       image.Image           // Stitched map tiles, nil to draw a blank grid
+	Features   []Feature
+	LabelScale int // Size of a label pixel in image pixels. Defaults to 2.
+}
+
+// Colors of the overlays.
+var (
+	playerColor        = color.NRGBA{0x3b, 0x82, 0xf6, 0xff}
+	hostileColor       = color.NRGBA{0xdc, 0x26, 0x26, 0xb0}
+	claimColor         = color.NRGBA{0x22, 0xc5, 0x5e, 0xff}
+	inactiveClaimColor = color.NRGBA{0x9c, 0xa3, 0xaf, 0xff}
+	outlineColor       = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	labelColor         = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	labelBackground    = color.NRGBA{0x00, 0x00, 0x00, 0xa0}
+	gridBackground     = color.NRGBA{0x1e, 0x1e, 0x1e, 0xff}
+	gridLineColor      = color.NRGBA{0x3a, 0x3a, 0x3a, 0xff}
+	gridAxisColor      = color.NRGBA{0x6b, 0x6b, 0x6b, 0xff}
+)
+
+const (
+	playerRadius     = 5 // Radius of player markers, in pixels
+	minClusterRadius = 6 // Radius of a cluster of one hostile, in pixels
+)
+
+// Create a map covering the given tiles, drawn over base if not nil.
+func NewMap(bounds sdtdclient.TileBounds, base image.Image) *Map {
+	return &Map{Bounds: bounds, Base: base, LabelScale: 2}
+}
+
+// Add a marker for each player, labelled with their name.
+func (m *Map) AddPlayers(players []sdtdclient.Player) {
+	for idx := range players {
+		m.Features = append(m.Features, Feature{
+			Kind:     Player,
+			Position: players[idx].Position,
+			Label:    players[idx].Name,
+		})
+	}
+}
+
+// Add a marker for each cluster of hostiles, labelled with the number of
+// hostiles. Hostiles within radius blocks of a cluster's center join it.
+func (m *Map) AddHostiles(hostiles []sdtdclient.EntityData, radius int) {
+	for _, cluster := range ClusterEntities(hostiles, radius) {
+		m.Features = append(m.Features, Feature{
+			Kind:     HostileCluster,
+			Position: cluster.Center,
+			Label:    fmt.Sprintf("%d", cluster.Count),
+			Size:     cluster.Count,
+		})
+	}
+}
+
+// Add the area protected by each land claim, labelled with the owner's name.
+func (m *Map) AddLandClaims(claims *sdtdclient.LandClaimsResponseM) {
+	for _, owner := range claims.ClaimOwners {
+		for _, claim := range owner.Claims {
+			m.Features = append(m.Features, Feature{
+				Kind:     LandClaim,
+				Position: claim,
+				Label:    owner.Name,
+				Size:     claims.ClaimSize,
+				Inactive: !owner.Active,
+			})
+		}
+	}
+}
+
+// Group entities into clusters, each entity joining the first cluster whose
+// center is within radius blocks horizontally, or starting a new one.
+func ClusterEntities(entities []sdtdclient.EntityData, radius int) []Cluster {
+	type sum struct{ x, y, z, count int }
+	sums := []sum{}
+	center := func(s sum) sdtdclient.Location {
+		return sdtdclient.Location{X: s.x / s.count, Y: s.y / s.count, Z: s.z / s.count}
+	}
+
+	for _, entity := range entities {
+		pos := entity.Position
+		joined := false
+		for idx := range sums {
+			c := center(sums[idx])
+			if math.Hypot(float64(pos.X-c.X), float64(pos.Z-c.Z)) <= float64(radius) {
+				sums[idx].x += pos.X
+				sums[idx].y += pos.Y
+				sums[idx].z += pos.Z
+				sums[idx].count++
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			sums = append(sums, sum{pos.X, pos.Y, pos.Z, 1})
+		}
+	}
+
+	clusters := make([]Cluster, len(sums))
+	for idx, s := range sums {
+		clusters[idx] = Cluster{Center: center(s), Count: s.count}
+	}
+	return clusters
+}
+
+// Returns the number of image pixels per block.
+func (m *Map) pixelsPerBlock() float64 {
+	return float64(sdtdclient.MapTileSize) / float64(sdtdclient.BlocksPerTile(m.Bounds.Zoom))
+}
+
+// Returns the radius in pixels of the marker of a hostile cluster.
+func clusterRadius(count int) int {
+	return minClusterRadius + int(2*math.Sqrt(float64(count-1)))
+}
+
+// Returns the features in drawing order: land claims, hostiles, then players.
+func (m *Map) orderedFeatures() []Feature {
+	ordered := make([]Feature, 0, len(m.Features))
+	for _, kind := range []Kind{LandClaim, HostileCluster, Player} {
+		for _, feature := range m.Features {
+			if feature.Kind == kind {
+				ordered = append(ordered, feature)
+			}
+		}
+	}
+	return ordered
+}
+
+// Render the map with its overlays.
+func (m *Map) Image() *image.RGBA {
+	width, height := m.Bounds.Size()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if m.Base != nil {
+		draw.Draw(img, img.Bounds(), m.Base, m.Base.Bounds().Min, draw.Src)
+	} else {
+		m.drawGrid(img)
+	}
+
+	scale := max(m.LabelScale, 1)
+	features := m.orderedFeatures()
+	for _, feature := range features {
+		at := m.Bounds.PixelAt(feature.Position)
+		switch feature.Kind {
+		case LandClaim:
+			half := max(int(float64(feature.Size)*m.pixelsPerBlock()/2), 1)
+			c := claimColor
+			if feature.Inactive {
+				c = inactiveClaimColor
+			}
+			area := image.Rect(at.X-half, at.Y-half, at.X+half+1, at.Y+half+1)
+			fillRect(img, area, color.NRGBA{c.R, c.G, c.B, 0x40})
+			strokeRect(img, area, c)
+		case HostileCluster:
+			fillCircle(img, at, clusterRadius(feature.Size), hostileColor)
+		case Player:
+			fillCircle(img, at, playerRadius+1, outlineColor)
+			fillCircle(img, at, playerRadius, playerColor)
+		}
+	}
+
+	// Labels go last so that no marker hides them.
+	for _, feature := range features {
+		at := m.Bounds.PixelAt(feature.Position)
+		size := textSize(feature.Label, scale)
+		if size.X == 0 {
+			continue
+		}
+
+		var origin image.Point
+		switch feature.Kind {
+		case HostileCluster:
+			origin = at.Sub(size.Div(2))
+		case LandClaim:
+			half := max(int(float64(feature.Size)*m.pixelsPerBlock()/2), 1)
+			origin = image.Point{at.X - size.X/2, at.Y + half + 2*scale}
+		default:
+			origin = image.Point{at.X - size.X/2, at.Y + playerRadius + 2*scale}
+		}
+		if feature.Kind != HostileCluster {
+			fillRect(img, image.Rectangle{origin, origin.Add(size)}.Inset(-scale), labelBackground)
+		}
+		drawText(img, origin, feature.Label, scale, labelColor)
+	}
+	return img
+}
+
+// Render the map with its overlays as PNG.
+func (m *Map) WritePNG(w io.Writer) error {
+	return png.Encode(w, m.Image())
+}
+
+// Draw a blank grid with one cell per tile, highlighting the X and Z axes.
+func (m *Map) drawGrid(img *image.RGBA) {
+	draw.Draw(img, img.Bounds(), image.NewUniform(gridBackground), image.Point{}, draw.Src)
+	width, height := m.Bounds.Size()
+	for x := 0; x <= width; x += sdtdclient.MapTileSize {
+		fillRect(img, image.Rect(x, 0, x+1, height), gridLineColor)
+	}
+	for y := 0; y <= height; y += sdtdclient.MapTileSize {
+		fillRect(img, image.Rect(0, y, width, y+1), gridLineColor)
+	}
+
+	origin := m.Bounds.PixelAt(sdtdclient.Location{})
+	fillRect(img, image.Rect(origin.X, 0, origin.X+1, height), gridAxisColor)
+	fillRect(img, image.Rect(0, origin.Y, width, origin.Y+1), gridAxisColor)
+}
+
+// Blend a color over the pixels of a rectangle.
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r.Intersect(img.Bounds()), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// Draw the one pixel wide outline of a rectangle.
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), c)
+	fillRect(img, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), c)
+	fillRect(img, image.Rect(r.Min.X, r.Min.Y+1, r.Min.X+1, r.Max.Y-1), c)
+	fillRect(img, image.Rect(r.Max.X-1, r.Min.Y+1, r.Max.X, r.Max.Y-1), c)
+}
+
+// Blend a color over the pixels of a disc.
+func fillCircle(img *image.RGBA, center image.Point, radius int, c color.Color) {
+	for dy := -radius; dy <= radius; dy++ {
+		half := int(math.Sqrt(float64(radius*radius - dy*dy)))
+		fillRect(img, image.Rect(center.X-half, center.Y+dy, center.X+half+1, center.Y+dy+1), c)
+	}
+}
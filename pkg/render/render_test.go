@@ -0,0 +1,143 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package render
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// World locations land on the pixel of the stitched tiles showing them, the
+// top row of the image being the northern (highest Z) edge.
+func TestPixelAt(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		zoom     int
+		from, to sdtdclient.Location
+		location sdtdclient.Location
+		want     image.Point
+	}{
+		{
+			name:     "zoom 4 origin",
+			zoom:     4,
+			from:     sdtdclient.Location{X: 0, Z: 0},
+			to:       sdtdclient.Location{X: 255, Z: 255},
+			location: sdtdclient.Location{X: 0, Y: 60, Z: 0},
+			want:     image.Point{X: 0, Y: 255},
+		},
+		{
+			name:     "zoom 4",
+			zoom:     4,
+			from:     sdtdclient.Location{X: 0, Z: 0},
+			to:       sdtdclient.Location{X: 255, Z: 255},
+			location: sdtdclient.Location{X: 130, Z: 200},
+			want:     image.Point{X: 130, Y: 55},
+		},
+		{
+			name:     "zoom 0",
+			zoom:     0,
+			from:     sdtdclient.Location{X: 0, Z: 0},
+			to:       sdtdclient.Location{X: 2047, Z: 2047},
+			location: sdtdclient.Location{X: 1024, Z: 1024},
+			want:     image.Point{X: 64, Y: 63},
+		},
+		{
+			name:     "zoom 4 negative",
+			zoom:     4,
+			from:     sdtdclient.Location{X: -128, Z: -128},
+			to:       sdtdclient.Location{X: -1, Z: -1},
+			location: sdtdclient.Location{X: -1, Z: -1},
+			want:     image.Point{X: 127, Y: 0},
+		},
+		{
+			name:     "zoom 4 negative corner",
+			zoom:     4,
+			from:     sdtdclient.Location{X: -200, Z: -200},
+			to:       sdtdclient.Location{X: 100, Z: 100},
+			location: sdtdclient.Location{X: -256, Z: -256},
+			want:     image.Point{X: 0, Y: 383},
+		},
+		{
+			name:     "zoom 0 negative",
+			zoom:     0,
+			from:     sdtdclient.Location{X: -2048, Z: -2048},
+			to:       sdtdclient.Location{X: 2047, Z: 2047},
+			location: sdtdclient.Location{X: -16, Z: -16},
+			want:     image.Point{X: 127, Y: 128},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			bounds, err := sdtdclient.TileBoundsFor(test.zoom, test.from, test.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bounds.PixelAt(test.location); got != test.want {
+				t.Errorf("PixelAt(%+v) = %v, want %v (bounds %+v)", test.location, got, test.want, bounds)
+			}
+		})
+	}
+}
+
+func TestClusterEntities(t *testing.T) {
+	entity := func(x, y, z int) sdtdclient.EntityData {
+		return sdtdclient.EntityData{Position: sdtdclient.Location{X: x, Y: y, Z: z}}
+	}
+
+	for _, test := range []struct {
+		name     string
+		entities []sdtdclient.EntityData
+		radius   int
+		want     []Cluster
+	}{
+		{
+			name:     "exactly at the radius",
+			entities: []sdtdclient.EntityData{entity(0, 60, 0), entity(3, 70, 4)},
+			radius:   5,
+			want:     []Cluster{{Center: sdtdclient.Location{X: 1, Y: 65, Z: 2}, Count: 2}},
+		},
+		{
+			name:     "just beyond the radius",
+			entities: []sdtdclient.EntityData{entity(0, 60, 0), entity(3, 70, 4)},
+			radius:   4,
+			want: []Cluster{
+				{Center: sdtdclient.Location{X: 0, Y: 60, Z: 0}, Count: 1},
+				{Center: sdtdclient.Location{X: 3, Y: 70, Z: 4}, Count: 1},
+			},
+		},
+		{
+			name:     "height ignored",
+			entities: []sdtdclient.EntityData{entity(-10, 0, -10), entity(-10, 200, -10)},
+			radius:   0,
+			want:     []Cluster{{Center: sdtdclient.Location{X: -10, Y: 100, Z: -10}, Count: 2}},
+		},
+		{
+			name:     "none",
+			entities: nil,
+			radius:   32,
+			want:     []Cluster{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := ClusterEntities(test.entities, test.radius)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// Returns the color as an SVG fill or stroke attribute value with its opacity.
+func svgColor(attr string, c color.NRGBA) string {
+	return fmt.Sprintf(`%s="#%02x%02x%02x" %s-opacity="%.2f"`, attr, c.R, c.G, c.B, attr, float64(c.A)/0xff)
+}
+
+// Returns text escaped for use in SVG.
+func svgEscape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// Render the map with its overlays as SVG. The base map is embedded as a PNG
+// image, and the overlays are vector shapes with text labels.
+func (m *Map) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	width, height := m.Bounds.Size()
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+
+	if m.Base != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, m.Base); err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, `<image width="%d" height="%d" href="data:image/png;base64,%s"/>`+"\n",
+			width, height, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	} else {
+		fmt.Fprintf(bw, `<rect width="%d" height="%d" %s/>`+"\n", width, height, svgColor("fill", gridBackground))
+		fmt.Fprintf(bw, `<g %s>`+"\n", svgColor("stroke", gridLineColor))
+		for x := 0; x <= width; x += sdtdclient.MapTileSize {
+			fmt.Fprintf(bw, `<line x1="%d.5" y1="0" x2="%d.5" y2="%d"/>`+"\n", x, x, height)
+		}
+		for y := 0; y <= height; y += sdtdclient.MapTileSize {
+			fmt.Fprintf(bw, `<line x1="0" y1="%d.5" x2="%d" y2="%d.5"/>`+"\n", y, width, y)
+		}
+		origin := m.Bounds.PixelAt(sdtdclient.Location{})
+		fmt.Fprintf(bw, `<line x1="%d.5" y1="0" x2="%d.5" y2="%d" %s/>`+"\n",
+			origin.X, origin.X, height, svgColor("stroke", gridAxisColor))
+		fmt.Fprintf(bw, `<line x1="0" y1="%d.5" x2="%d" y2="%d.5" %s/>`+"\n",
+			origin.Y, width, origin.Y, svgColor("stroke", gridAxisColor))
+		fmt.Fprintln(bw, `</g>`)
+	}
+
+	fontSize := 6 * max(m.LabelScale, 1)
+	fmt.Fprintf(bw, `<g font-family="monospace" font-size="%d" text-anchor="middle">`+"\n", fontSize)
+	for _, feature := range m.orderedFeatures() {
+		at := m.Bounds.PixelAt(feature.Position)
+		label := svgEscape(feature.Label)
+		switch feature.Kind {
+		case LandClaim:
+			size := float64(feature.Size) * m.pixelsPerBlock()
+			c := claimColor
+			if feature.Inactive {
+				c = inactiveClaimColor
+			}
+			fill := color.NRGBA{c.R, c.G, c.B, 0x40}
+			fmt.Fprintf(bw, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" %s %s><title>%s</title></rect>`+"\n",
+				float64(at.X)-size/2, float64(at.Y)-size/2, size, size, svgColor("fill", fill), svgColor("stroke", c), label)
+			fmt.Fprintf(bw, `<text x="%d" y="%.1f" %s>%s</text>`+"\n",
+				at.X, float64(at.Y)+size/2+float64(fontSize), svgColor("fill", labelColor), label)
+		case HostileCluster:
+			fmt.Fprintf(bw, `<circle cx="%d" cy="%d" r="%d" %s/>`+"\n",
+				at.X, at.Y, clusterRadius(feature.Size), svgColor("fill", hostileColor))
+			fmt.Fprintf(bw, `<text x="%d" y="%d" dominant-baseline="central" %s>%s</text>`+"\n",
+				at.X, at.Y, svgColor("fill", labelColor), label)
+		case Player:
+			fmt.Fprintf(bw, `<circle cx="%d" cy="%d" r="%d" %s %s/>`+"\n",
+				at.X, at.Y, playerRadius, svgColor("fill", playerColor), svgColor("stroke", outlineColor))
+			fmt.Fprintf(bw, `<text x="%d" y="%d" %s>%s</text>`+"\n",
+				at.X, at.Y+playerRadius+fontSize+2, svgColor("fill", labelColor), label)
+		}
+	}
+	fmt.Fprintln(bw, `</g>`)
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
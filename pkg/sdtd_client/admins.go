@@ -0,0 +1,199 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/thelande/sdtd_client/internal/consoletext"
+)
+
+// Server admins are managed through the admin user permission module of the
+// web API. When the server does not provide it, admins are listed, added and
+// removed with the admin console command instead.
+
+var steamGroupRe = regexp.MustCompile(`^[0-9]+$`)
+
+// Reports whether admins must be managed with the admin console command.
+func (c *SDTDClient) adminsViaConsole() bool {
+	return c.moduleMissing(ModuleAdminUser)
+}
+
+// Validate a Steam group ID for use in a console command.
+func steamGroupArg(id string) (string, error) {
+	if !steamGroupRe.MatchString(id) {
+		return "", fmt.Errorf("%w: invalid Steam group ID %q", ErrInvalidArgument, id)
+	}
+	return id, nil
+}
+
+// Fetch the server admins, users and groups alike.
+func (c *SDTDClient) GetAdmins() (*AdminsData, error) {
+	return c.GetAdminsContext(context.Background())
+}
+
+// Context-aware variant of GetAdmins.
+func (c *SDTDClient) GetAdminsContext(ctx context.Context) (*AdminsData, error) {
+	path := "/api/adminuser"
+	admins := AdminsResponse{}
+	err := GetContext(ctx, c, path, &admins, nil)
+	if errors.Is(err, ErrCapabilityMissing) || errors.Is(err, ErrNotFound) {
+		return c.adminListViaConsole(ctx)
+	} else if err != nil {
+		return nil, err
+	}
+	return &admins.Data, nil
+}
+
+// List the admins with the admin console command.
+func (c *SDTDClient) adminListViaConsole(ctx context.Context) (*AdminsData, error) {
+	result, err := c.ExecuteCommandContext(ctx, "admin list")
+	if err != nil {
+		return nil, err
+	}
+	list, err := consoletext.ParseAdminList(result.Result)
+	if err != nil {
+		return nil, fmt.Errorf("admin list: %w", err)
+	}
+
+	admins := &AdminsData{
+		Users:  make([]AdminUser, 0, len(list.Users)),
+		Groups: make([]AdminGroup, 0, len(list.Groups)),
+	}
+	for _, user := range list.Users {
+		admins.Users = append(admins.Users, AdminUser{
+			UserID:          user.ID,
+			Name:            user.Name,
+			PermissionLevel: user.Level,
+		})
+	}
+	for _, group := range list.Groups {
+		admins.Groups = append(admins.Groups, AdminGroup{
+			GroupID:               group.ID,
+			Name:                  group.Name,
+			PermissionLevelNormal: group.Level,
+			PermissionLevelMods:   group.OfficerLevel,
+		})
+	}
+	return admins, nil
+}
+
+// Grant a user the given permission level. Adding an existing admin changes
+// their permission level.
+func (c *SDTDClient) AddAdminUser(id string, name string, level int) error {
+	return c.AddAdminUserContext(context.Background(), id, name, level)
+}
+
+// Context-aware variant of AddAdminUser.
+func (c *SDTDClient) AddAdminUserContext(ctx context.Context, id string, name string, level int) error {
+	if c.adminsViaConsole() {
+		target, err := PlatformID(id).commandArg()
+		if err != nil {
+			return err
+		}
+		command, err := buildCommand("admin add", []string{target, strconv.Itoa(level)}, name)
+		_, err = c.executeBuilt(ctx, command, err)
+		return err
+	}
+
+	path := fmt.Sprintf("/api/adminuser/user/%v", id)
+	body, err := json.Marshal(AdminUserRequestBody{Name: name, PermissionLevel: level})
+	if err != nil {
+		return err
+	}
+	return PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+}
+
+// Remove a user from the admins.
+func (c *SDTDClient) DeleteAdminUser(id string) error {
+	return c.DeleteAdminUserContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteAdminUser.
+func (c *SDTDClient) DeleteAdminUserContext(ctx context.Context, id string) error {
+	if c.adminsViaConsole() {
+		target, err := PlatformID(id).commandArg()
+		if err != nil {
+			return err
+		}
+		command, err := buildCommand("admin remove", []string{target}, "")
+		_, err = c.executeBuilt(ctx, command, err)
+		return err
+	}
+
+	path := fmt.Sprintf("/api/adminuser/user/%v", id)
+	return DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+}
+
+// Grant the members of a Steam group the given permission levels, one for
+// regular members and one for group officers.
+func (c *SDTDClient) AddAdminGroup(id string, name string, levelNormal int, levelMods int) error {
+	return c.AddAdminGroupContext(context.Background(), id, name, levelNormal, levelMods)
+}
+
+// Context-aware variant of AddAdminGroup.
+func (c *SDTDClient) AddAdminGroupContext(ctx context.Context, id string, name string, levelNormal int, levelMods int) error {
+	if c.adminsViaConsole() {
+		target, err := steamGroupArg(id)
+		if err != nil {
+			return err
+		}
+		command, err := buildCommand(
+			"admin addgroup",
+			[]string{target, strconv.Itoa(levelNormal), strconv.Itoa(levelMods)},
+			name,
+		)
+		_, err = c.executeBuilt(ctx, command, err)
+		return err
+	}
+
+	path := fmt.Sprintf("/api/adminuser/group/%v", id)
+	body, err := json.Marshal(AdminGroupRequestBody{
+		Name:                  name,
+		PermissionLevelNormal: levelNormal,
+		PermissionLevelMods:   levelMods,
+	})
+	if err != nil {
+		return err
+	}
+	return PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+}
+
+// Remove a Steam group from the admins.
+func (c *SDTDClient) DeleteAdminGroup(id string) error {
+	return c.DeleteAdminGroupContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteAdminGroup.
+func (c *SDTDClient) DeleteAdminGroupContext(ctx context.Context, id string) error {
+	if c.adminsViaConsole() {
+		target, err := steamGroupArg(id)
+		if err != nil {
+			return err
+		}
+		command, err := buildCommand("admin removegroup", []string{target}, "")
+		_, err = c.executeBuilt(ctx, command, err)
+		return err
+	}
+
+	path := fmt.Sprintf("/api/adminuser/group/%v", id)
+	return DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+const adminListOutput = `Defined User Permissions:
+  Level: UserID (Player name if online, stored name)
+      0: Steam_76561198000000001 (Steve, Steve)
+Defined Group Permissions:
+  Level regular / officer: SteamGroupID (Stored name)
+      2 /     1: 103582791429521412 (Moderators)
+`
+
+// Servers without the admin user permission module list their admins with
+// the admin console command, whether the module is known to be missing or
+// the endpoint is simply not found.
+func TestGetAdminsViaConsole(t *testing.T) {
+	want := &sdtdclient.AdminsData{
+		Users: []sdtdclient.AdminUser{
+			{UserID: "Steam_76561198000000001", Name: "Steve", PermissionLevel: 0},
+		},
+		Groups: []sdtdclient.AdminGroup{
+			{GroupID: "103582791429521412", Name: "Moderators", PermissionLevelNormal: 2, PermissionLevelMods: 1},
+		},
+	}
+
+	for _, test := range []struct {
+		name    string
+		connect bool
+	}{
+		{"module missing", true},
+		{"capabilities unknown", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/serverinfo":
+					w.Write([]byte(`{"data":[{"name":"Version","type":"string","value":"V 1.0 (b333)"}]}`))
+				case "/userstatus":
+					w.Write([]byte(`{"data":{"permissionLevel":0,"permissions":[
+						{"module":"webapi.serverinfo","allowed":{"GET":true}},
+						{"module":"webapi.command","allowed":{"POST":true}}]}}`))
+				case "/api/command":
+					body := map[string]any{"data": map[string]string{"command": "admin", "parameters": "list", "result": adminListOutput}}
+					json.NewEncoder(w).Encode(body)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client, err := sdtdclient.NewSDTDClientWithOptions(
+				server.URL,
+				&sdtdclient.SDTDAuth{TokenName: "name", TokenSecret: "secret"},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.connect {
+				if err := client.Connect(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			admins, err := client.GetAdmins()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(admins, want) {
+				t.Errorf("got %+v, want %+v", admins, want)
+			}
+		})
+	}
+}
@@ -15,15 +15,143 @@ limitations under the License.
 */
 package sdtdclient
 
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
 // Receivers for Alloc's Server Fixes API endpoints.
 
 // Returns all players known to the server. Requires Alloc's Server Fixes Mod.
 func (c *SDTDClient) GetAllPlayersM() (*PlayersResponseM, error) {
+	return c.GetAllPlayersMContext(context.Background())
+}
+
+// Context-aware variant of GetAllPlayersM.
+func (c *SDTDClient) GetAllPlayersMContext(ctx context.Context) (*PlayersResponseM, error) {
 	path := "/api/getplayerlist"
 	players := PlayersResponseM{}
-	err := GetM(c, path, &players, nil)
+	err := GetMContext(ctx, c, path, &players, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &players, nil
 }
+
+// Returns the hostile entities currently alive. Requires Alloc's Server Fixes
+// Mod.
+func (c *SDTDClient) GetHostilesM() (*EntitiesResponseM, error) {
+	return c.GetHostilesMContext(context.Background())
+}
+
+// Context-aware variant of GetHostilesM.
+func (c *SDTDClient) GetHostilesMContext(ctx context.Context) (*EntitiesResponseM, error) {
+	path := "/api/gethostilelocation"
+	hostiles := EntitiesResponseM{}
+	err := GetMContext(ctx, c, path, &hostiles, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &hostiles, nil
+}
+
+// Returns the animals currently alive. Requires Alloc's Server Fixes Mod.
+func (c *SDTDClient) GetAnimalsM() (*EntitiesResponseM, error) {
+	return c.GetAnimalsMContext(context.Background())
+}
+
+// Context-aware variant of GetAnimalsM.
+func (c *SDTDClient) GetAnimalsMContext(ctx context.Context) (*EntitiesResponseM, error) {
+	path := "/api/getanimalslocation"
+	animals := EntitiesResponseM{}
+	err := GetMContext(ctx, c, path, &animals, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &animals, nil
+}
+
+// Returns the land claims of all players. Requires Alloc's Server Fixes Mod.
+func (c *SDTDClient) GetLandClaimsM() (*LandClaimsResponseM, error) {
+	return c.GetLandClaimsMContext(context.Background())
+}
+
+// Context-aware variant of GetLandClaimsM.
+func (c *SDTDClient) GetLandClaimsMContext(ctx context.Context) (*LandClaimsResponseM, error) {
+	path := "/api/getlandclaims"
+	claims := LandClaimsResponseM{}
+	err := GetMContext(ctx, c, path, &claims, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// Returns the inventory of the player with the given platform ID. Requires
+// Alloc's Server Fixes Mod.
+func (c *SDTDClient) GetPlayerInventoryM(platformID string) (*PlayerInventoryM, error) {
+	return c.GetPlayerInventoryMContext(context.Background(), platformID)
+}
+
+// Context-aware variant of GetPlayerInventoryM.
+func (c *SDTDClient) GetPlayerInventoryMContext(ctx context.Context, platformID string) (*PlayerInventoryM, error) {
+	path := "/api/getplayerinventory"
+	params := url.Values{}
+	params.Add("userid", platformID)
+
+	inventory := PlayerInventoryM{}
+	err := GetMContext(ctx, c, path, &inventory, &params)
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// Returns the inventories of all players. Requires Alloc's Server Fixes Mod.
+func (c *SDTDClient) GetPlayerInventoriesM() (*PlayerInventoriesResponseM, error) {
+	return c.GetPlayerInventoriesMContext(context.Background())
+}
+
+// Context-aware variant of GetPlayerInventoriesM.
+func (c *SDTDClient) GetPlayerInventoriesMContext(ctx context.Context) (*PlayerInventoriesResponseM, error) {
+	path := "/api/getplayerinventories"
+	inventories := PlayerInventoriesResponseM{}
+	err := GetMContext(ctx, c, path, &inventories, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &inventories, nil
+}
+
+// Run a console command through Alloc's Server Fixes and return its output.
+// Requires Alloc's Server Fixes Mod. Although the endpoint takes GET requests,
+// the command is sent exactly once and its output is never cached, as it may
+// change the game state.
+func (c *SDTDClient) ExecuteCommandM(command string) (*CommandResultData, error) {
+	return c.ExecuteCommandMContext(context.Background(), command)
+}
+
+// Context-aware variant of ExecuteCommandM.
+func (c *SDTDClient) ExecuteCommandMContext(ctx context.Context, command string) (*CommandResultData, error) {
+	path := "/api/executeconsolecommand"
+	params := url.Values{}
+	params.Add("command", command)
+
+	if !c.AllocsEnabled() {
+		return nil, ErrAllocsModNotInstalled
+	}
+	req := c.newRequest("GET", path, &params, nil)
+	req.noRetry, req.noCache = true, true
+	body, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := CommandResultResponseM{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.CommandResultData, nil
+}
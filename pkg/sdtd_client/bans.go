@@ -0,0 +1,108 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Returns the time the ban expires, or the zero time if Until is empty.
+func (b BannedData) Expiry() (time.Time, error) {
+	return ParseTimestamp(b.Until)
+}
+
+// Decode a ban, accepting the timestamp formats understood by ParseTimestamp
+// for the expiry.
+func (b *BanEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name   string `json:"name"`
+		UserID string `json:"userId"`
+		Until  string `json:"bannedUntil"`
+		Reason string `json:"banReason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	until, err := ParseTimestamp(raw.Until)
+	if err != nil {
+		return err
+	}
+	*b = BanEntry{Name: raw.Name, UserID: raw.UserID, Until: until, Reason: raw.Reason}
+	return nil
+}
+
+// Fetch the list of banned players.
+func (c *SDTDClient) GetBans() (*BlacklistResponse, error) {
+	return c.GetBansContext(context.Background())
+}
+
+// Context-aware variant of GetBans.
+func (c *SDTDClient) GetBansContext(ctx context.Context) (*BlacklistResponse, error) {
+	path := "/api/blacklist"
+	bans := BlacklistResponse{}
+	err := GetContext(ctx, c, path, &bans, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &bans, nil
+}
+
+// Ban a player until the given time, with an optional reason.
+func (c *SDTDClient) AddBan(id string, name string, until time.Time, reason string) error {
+	return c.AddBanContext(context.Background(), id, name, until, reason)
+}
+
+// Context-aware variant of AddBan.
+func (c *SDTDClient) AddBanContext(ctx context.Context, id string, name string, until time.Time, reason string) error {
+	if until.IsZero() {
+		return fmt.Errorf("%w: the ban expiry is not set", ErrInvalidArgument)
+	}
+
+	path := fmt.Sprintf("/api/blacklist/user/%v", id)
+	data := BlacklistRequestBody{
+		Name:   name,
+		Until:  until.UTC().Format(time.RFC3339),
+		Reason: reason,
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Lift the ban of a player.
+func (c *SDTDClient) DeleteBan(id string) error {
+	return c.DeleteBanContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteBan.
+func (c *SDTDClient) DeleteBanContext(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/blacklist/user/%v", id)
+	err := DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
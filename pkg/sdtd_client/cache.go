@@ -0,0 +1,176 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	path    string
+	body    []byte
+	expires time.Time
+}
+
+// A GET request in flight, shared by every caller asking for the same URL.
+type inflightCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// Caches GET responses for a configurable time and collapses identical
+// concurrent GET requests into a single round trip.
+type responseCache struct {
+	defaultTTL    time.Duration
+	endpointTTL   map[string]time.Duration // Keyed by path prefix
+	invalidations map[string][]string      // Mutated path prefix to cached path prefixes
+
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	calls      map[string]*inflightCall
+	generation uint64 // Incremented on each invalidation
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		endpointTTL:   map[string]time.Duration{},
+		invalidations: map[string][]string{},
+		entries:       map[string]cacheEntry{},
+		calls:         map[string]*inflightCall{},
+	}
+}
+
+// Return the time responses from the given path are cached for.
+func (rc *responseCache) ttl(path string) time.Duration {
+	if ttl, ok := matchPathPrefix(rc.endpointTTL, path); ok {
+		return ttl
+	}
+	return rc.defaultTTL
+}
+
+// Return the cached response for the given path and params, or call fetch to
+// get it. Concurrent callers for the same path and params share a single call
+// to fetch.
+func (rc *responseCache) do(ctx context.Context, path string, params url.Values, fetch func() ([]byte, error)) ([]byte, error) {
+	path = normalizePath(path)
+	key := path
+	if len(params) > 0 {
+		key += "?" + params.Encode()
+	}
+
+	for {
+		rc.mu.Lock()
+		if entry, ok := rc.entries[key]; ok {
+			if time.Now().Before(entry.expires) {
+				rc.mu.Unlock()
+				return slices.Clone(entry.body), nil
+			}
+			delete(rc.entries, key)
+		}
+
+		if call, ok := rc.calls[key]; ok {
+			rc.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			// The caller that made the request gave up, try again with our
+			// own context.
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return slices.Clone(call.body), call.err
+		}
+
+		call := &inflightCall{done: make(chan struct{})}
+		rc.calls[key] = call
+		generation := rc.generation
+		rc.mu.Unlock()
+
+		call.body, call.err = fetch()
+
+		rc.mu.Lock()
+		delete(rc.calls, key)
+		// Skip storing the response if the cache was invalidated while the
+		// request was running, as it may be stale.
+		if ttl := rc.ttl(path); call.err == nil && ttl > 0 && generation == rc.generation {
+			rc.entries[key] = cacheEntry{
+				path:    path,
+				body:    call.body,
+				expires: time.Now().Add(ttl),
+			}
+		}
+		rc.mu.Unlock()
+		close(call.done)
+
+		return slices.Clone(call.body), call.err
+	}
+}
+
+// Evict the entries related to a successful mutating request to the given path.
+// Entries sharing the first two path segments (e.g. "/api/whitelist") are
+// evicted, along with those configured by WithCacheInvalidation.
+func (rc *responseCache) invalidateFor(path string) {
+	path = normalizePath(path)
+
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	resource := "/" + strings.Join(segments[:min(2, len(segments))], "/")
+	prefixes := []string{resource}
+	for prefix, related := range rc.invalidations {
+		if strings.HasPrefix(path, prefix) {
+			prefixes = append(prefixes, related...)
+		}
+	}
+	rc.invalidate(prefixes...)
+}
+
+// Evict the entries whose path starts with one of the given prefixes, or every
+// entry if no prefix is given.
+func (rc *responseCache) invalidate(prefixes ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.generation++
+	for key, entry := range rc.entries {
+		if len(prefixes) == 0 || slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(entry.path, normalizePath(prefix))
+		}) {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// Evict the cached responses of the paths starting with one of the given
+// prefixes, or all cached responses if no prefix is given. Does nothing if the
+// response cache is not enabled.
+func (c *SDTDClient) InvalidateCache(pathPrefixes ...string) {
+	if c.cache != nil {
+		c.cache.invalidate(pathPrefixes...)
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Names of the web API permission modules, as reported by GetUserStatus.
+const (
+	ModuleServerInfo        = "webapi.serverinfo"
+	ModuleServerStats       = "webapi.serverstats"
+	ModuleGamePrefs         = "webapi.gameprefs"
+	ModulePlayer            = "webapi.player"
+	ModuleHostile           = "webapi.hostile"
+	ModuleAnimal            = "webapi.animal"
+	ModuleLog               = "webapi.log"
+	ModuleWhitelist         = "webapi.whitelist"
+	ModuleBlacklist         = "webapi.blacklist"
+	ModuleAdminUser         = "webapi.adminuser"
+	ModuleCommandPermission = "webapi.commandpermission"
+	ModuleWebModules        = "webapi.webmodules"
+	ModuleMarkers           = "webapi.markers"
+	ModuleCommand           = "webapi.command"
+)
+
+// Maps API path prefixes to the permission module serving them.
+var endpointModules = map[string]string{
+	"/api/serverinfo":        ModuleServerInfo,
+	"/api/serverstats":       ModuleServerStats,
+	"/api/gameprefs":         ModuleGamePrefs,
+	"/api/player":            ModulePlayer,
+	"/api/hostile":           ModuleHostile,
+	"/api/animal":            ModuleAnimal,
+	"/api/log":               ModuleLog,
+	"/api/whitelist":         ModuleWhitelist,
+	"/api/blacklist":         ModuleBlacklist,
+	"/api/adminuser":         ModuleAdminUser,
+	"/api/commandpermission": ModuleCommandPermission,
+	"/api/webmodules":        ModuleWebModules,
+	"/api/markers":           ModuleMarkers,
+	"/api/command":           ModuleCommand,
+}
+
+// Endpoints provided by Alloc's Server Fixes.
+var allocsEndpoints = []string{
+	"/api/getstats",
+	"/api/getplayerlist",
+	"/api/getplayersonline",
+	"/api/getplayerslocation",
+	"/api/getplayerinventory",
+	"/api/getplayerinventories",
+	"/api/gethostilelocation",
+	"/api/getanimalslocation",
+	"/api/getlandclaims",
+	"/api/executeconsolecommand",
+	"/api/getwebuiupdates",
+}
+
+// What the server and the client's token support, as discovered by Connect.
+type Capabilities struct {
+	ServerVersion string // Game version, e.g. "V 1.0"
+	ServerBuild   string // Game build, e.g. "b333"
+
+	// Verbs the token may use on each web API module, keyed by the lower-case
+	// module name (e.g. "webapi.log"). Modules missing from the map are not
+	// available on the server.
+	Modules map[string]AllowedVerbs
+
+	AllocsServerFixes bool     // Whether Alloc's Server Fixes responded
+	ModEndpoints      []string // Paths of the mod endpoints available
+}
+
+// Error returned when a request targets a module the server does not provide.
+// Matches ErrCapabilityMissing with errors.Is.
+type CapabilityError struct {
+	Module string
+	Path   string
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("%s: module %s is not available on the server (requested %s)", ErrCapabilityMissing, e.Module, e.Path)
+}
+
+func (e *CapabilityError) Unwrap() error {
+	return ErrCapabilityMissing
+}
+
+// Reports whether the server provides the given module.
+func (c *Capabilities) HasModule(module string) bool {
+	_, ok := c.Modules[strings.ToLower(module)]
+	return ok
+}
+
+// Returns the verbs the token may use on the given module.
+func (c *Capabilities) Allowed(module string) AllowedVerbs {
+	return c.Modules[strings.ToLower(module)]
+}
+
+// Reports whether the server is known to lack the given module. Returns false
+// when the capabilities have not been discovered.
+func (c *SDTDClient) moduleMissing(module string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	caps := c.capabilities
+	return caps != nil && len(caps.Modules) > 0 && !caps.HasModule(module)
+}
+
+// Returns a copy of the capabilities discovered by Connect, or nil if the client
+// has not connected yet.
+func (c *SDTDClient) Capabilities() *Capabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.capabilities == nil {
+		return nil
+	}
+	caps := *c.capabilities
+	caps.Modules = maps.Clone(c.capabilities.Modules)
+	caps.ModEndpoints = slices.Clone(c.capabilities.ModEndpoints)
+	return &caps
+}
+
+var versionRe = regexp.MustCompile(`^(.*?)\s*\((b\d+)\)\s*$`)
+
+// Extract the game version and build from the server info, which reports them
+// as e.g. "V 1.0 (b333)".
+func parseServerVersion(info *ServerInfoResponse) (version string, build string) {
+	for _, setting := range info.Data {
+		if setting.Name != "Version" && setting.Name != "ServerVersion" {
+			continue
+		}
+		value, ok := setting.Value.(string)
+		if !ok {
+			continue
+		}
+		if m := versionRe.FindStringSubmatch(value); m != nil {
+			return m[1], m[2]
+		}
+		return value, ""
+	}
+	return "", ""
+}
+
+// Build the module table from the user's permissions.
+func modulesFromPermissions(permissions []Permission) map[string]AllowedVerbs {
+	modules := make(map[string]AllowedVerbs, len(permissions))
+	for _, permission := range permissions {
+		modules[strings.ToLower(permission.Module)] = permission.Allowed
+	}
+	return modules
+}
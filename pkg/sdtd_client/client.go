@@ -17,13 +17,16 @@ package sdtdclient
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -34,20 +37,41 @@ type SDTDAuth struct {
 	TokenSecret string
 }
 
+// A client for the 7 Days to Die web server API. A client is safe for
+// concurrent use by multiple goroutines. Host and Auth must not be modified
+// once the client is in use.
 type SDTDClient struct {
 	Host string
 	Auth *SDTDAuth
 
 	// Private fields
+	client    *http.Client
+	logger    *log.Logger
+	userAgent string
+	checkPerm bool           // Reject requests the token lacks permission for
+	inFlight  chan struct{}  // Semaphore limiting concurrent requests, nil if unlimited
+	limiter   *rateLimiter   // Nil if requests are not rate limited
+	cache     *responseCache // Nil if responses are not cached
+
+	// Guards the fields below, which may change while requests are running.
+	mu            sync.RWMutex
 	allocsEnabled bool
-	client        *http.Client
-	logger        *log.Logger
+	capabilities  *Capabilities
+	retryPolicy   *RetryPolicy
+	middleware    []Middleware
+	handler       Handler
 }
 
 // Perform a GET request against the API and return the populated response
 // struct.
 func Get[R Response](c *SDTDClient, path string, resp *R, params *url.Values) error {
-	body, err := c.Do("GET", path, params, nil)
+	return GetContext(context.Background(), c, path, resp, params)
+}
+
+// Perform a GET request against the API using the given context and return the
+// populated response struct.
+func GetContext[R Response](ctx context.Context, c *SDTDClient, path string, resp *R, params *url.Values) error {
+	body, err := c.DoContext(ctx, "GET", path, params, nil)
 	if err != nil {
 		return err
 	}
@@ -65,7 +89,13 @@ func Get[R Response](c *SDTDClient, path string, resp *R, params *url.Values) er
 // Perform a POST request against the API and return the populated response
 // struct.
 func Post[R Response](c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
-	body, err := c.Do("POST", path, params, data)
+	return PostContext(context.Background(), c, path, resp, params, data)
+}
+
+// Perform a POST request against the API using the given context and return
+// the populated response struct.
+func PostContext[R Response](ctx context.Context, c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
+	body, err := c.DoContext(ctx, "POST", path, params, data)
 	if err != nil {
 		return err
 	}
@@ -80,10 +110,40 @@ func Post[R Response](c *SDTDClient, path string, resp *R, params *url.Values, d
 	return nil
 }
 
+// Perform a PUT request against the API and return the populated response
+// struct.
+func Put[R Response](c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
+	return PutContext(context.Background(), c, path, resp, params, data)
+}
+
+// Perform a PUT request against the API using the given context and return
+// the populated response struct.
+func PutContext[R Response](ctx context.Context, c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
+	body, err := c.DoContext(ctx, "PUT", path, params, data)
+	if err != nil {
+		return err
+	}
+
+	if len(body) > 0 {
+		err = json.Unmarshal(body, resp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Perform a DELETE request against the API and return the populated response
 // struct.
 func Delete[R Response](c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
-	body, err := c.Do("DELETE", path, params, data)
+	return DeleteContext(context.Background(), c, path, resp, params, data)
+}
+
+// Perform a DELETE request against the API using the given context and return
+// the populated response struct.
+func DeleteContext[R Response](ctx context.Context, c *SDTDClient, path string, resp *R, params *url.Values, data []byte) error {
+	body, err := c.DoContext(ctx, "DELETE", path, params, data)
 	if err != nil {
 		return err
 	}
@@ -103,36 +163,86 @@ func Delete[R Response](c *SDTDClient, path string, resp *R, params *url.Values,
 // Perform a GET request against the Alloc's Server Fixes API and return the
 // populated response struct.
 func GetM[R Response](c *SDTDClient, path string, resp *R, params *url.Values) error {
-	if !c.allocsEnabled {
+	return GetMContext(context.Background(), c, path, resp, params)
+}
+
+// Perform a GET request against the Alloc's Server Fixes API using the given
+// context and return the populated response struct.
+func GetMContext[R Response](ctx context.Context, c *SDTDClient, path string, resp *R, params *url.Values) error {
+	if !c.AllocsEnabled() {
 		return ErrAllocsModNotInstalled
 	}
-	return Get(c, path, resp, params)
+	return GetContext(ctx, c, path, resp, params)
 }
 
+// Create a new client. Use NewSDTDClientWithOptions for more control over
+// the underlying HTTP client.
 func NewSDTDClient(host string, auth *SDTDAuth, sslVerify bool, logger *log.Logger) (*SDTDClient, error) {
+	return NewSDTDClientWithOptions(host, auth, WithSSLVerify(sslVerify), WithLogger(logger))
+}
+
+// Create a new client configured by the given options.
+func NewSDTDClientWithOptions(host string, auth *SDTDAuth, opts ...Option) (*SDTDClient, error) {
 	if len(host) == 0 {
 		return nil, ErrNoHostSet
 	}
-	if host[0:7] != "http://" && host[0:8] != "https://" {
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
 		return nil, ErrInvalidHostScheme
 	}
 	if auth == nil {
 		return nil, ErrNilAuth
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: !sslVerify},
+	cfg := clientConfig{retryPolicy: DefaultRetryPolicy()}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient, err := cfg.buildHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	logger := cfg.logger
+	if logger == nil {
+		nop := log.NewNopLogger()
+		logger = &nop
 	}
-	client := SDTDClient{
+
+	client := &SDTDClient{
 		Host:          host,
 		Auth:          auth,
 		allocsEnabled: false,
-		client: &http.Client{
-			Transport: tr,
-		},
-		logger: logger,
-	}
-	return &client, nil
+		client:        httpClient,
+		logger:        logger,
+		retryPolicy:   cfg.retryPolicy,
+		userAgent:     cfg.userAgent,
+		limiter:       cfg.rateLimiter,
+		cache:         cfg.cache,
+		checkPerm:     cfg.permissionChecks,
+	}
+	if cfg.maxInFlight > 0 {
+		client.inFlight = make(chan struct{}, cfg.maxInFlight)
+	}
+	client.Use(cfg.middleware...)
+	return client, nil
+}
+
+// Reports whether Alloc's Server Fixes were detected by Connect.
+func (c *SDTDClient) AllocsEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.allocsEnabled
+}
+
+// Replace the policy used to retry failed requests. A nil policy disables
+// retries.
+func (c *SDTDClient) SetRetryPolicy(policy *RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retryPolicy = policy
 }
 
 // Return the authentication headers for communicating with the API server.
@@ -146,17 +256,176 @@ func (c *SDTDClient) GetHeaders() http.Header {
 	// not care).
 	headers["X-SDTD-API-TOKENNAME"] = []string{c.Auth.TokenName}
 	headers["X-SDTD-API-SECRET"] = []string{c.Auth.TokenSecret}
+
+	if c.userAgent != "" {
+		headers.Set("User-Agent", c.userAgent)
+	}
 	return headers
 }
 
 // Make a request against the API.
 func (c *SDTDClient) Do(method string, path string, params *url.Values, data []byte) ([]byte, error) {
+	return c.DoContext(context.Background(), method, path, params, data)
+}
+
+// Make a request against the API using the given context. Cancelling the
+// context or exceeding its deadline aborts the request. Failed requests are
+// retried according to the client's retry policy, each attempt passing through
+// the client's middleware chain. When the response cache is enabled, GET
+// requests may be answered from the cache or share the response of an
+// identical request already in flight.
+func (c *SDTDClient) DoContext(ctx context.Context, method string, path string, params *url.Values, data []byte) ([]byte, error) {
+	return c.do(ctx, c.newRequest(method, path, params, data))
+}
+
+// Build a request with the client's headers.
+func (c *SDTDClient) newRequest(method string, path string, params *url.Values, data []byte) *Request {
 	headers := c.GetHeaders()
 	if method != "GET" && method != "DELETE" {
 		headers["Content-Type"] = []string{"application/json"}
 	}
 
-	fullPath, err := url.JoinPath(c.Host, path)
+	req := &Request{
+		Method: method,
+		Path:   path,
+		Header: headers,
+		Body:   data,
+	}
+	if params != nil {
+		req.Params = *params
+	}
+	return req
+}
+
+// Make a request, going through the response cache for GET requests unless
+// the request bypasses it. Other requests evict the related cache entries when
+// they succeed.
+func (c *SDTDClient) do(ctx context.Context, req *Request) ([]byte, error) {
+	if err := c.preflight(ctx, req.Method, req.Path); err != nil {
+		return nil, err
+	}
+
+	if c.cache == nil {
+		return responseBody(c.send(ctx, req))
+	}
+
+	if req.Method == "GET" && !req.noCache {
+		return c.cache.do(ctx, req.Path, req.Params, func() ([]byte, error) {
+			return responseBody(c.send(ctx, req))
+		})
+	}
+
+	body, err := responseBody(c.send(ctx, req))
+	if err == nil {
+		c.cache.invalidateFor(req.Path)
+	}
+	return body, err
+}
+
+// Make a GET request and return the raw response, bypassing the response
+// cache. The given headers are added to the request. Unlike DoContext, a 304
+// Not Modified response is returned without an error, making conditional
+// requests possible.
+func (c *SDTDClient) getRaw(ctx context.Context, path string, header http.Header) (*RawResponse, error) {
+	req := &Request{
+		Method: "GET",
+		Path:   path,
+		Header: c.GetHeaders(),
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if err := c.preflight(ctx, req.Method, path); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(ctx, req)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+	return resp, err
+}
+
+// Return the body of a response, or the error.
+func responseBody(resp *RawResponse, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Send a request, retrying it according to the client's retry policy unless
+// the request must not be repeated.
+func (c *SDTDClient) send(ctx context.Context, req *Request) (*RawResponse, error) {
+	c.mu.RLock()
+	policy, handler := c.retryPolicy, c.handler
+	c.mu.RUnlock()
+
+	attempts := 1
+	if policy != nil && policy.MaxAttempts > 1 && policy.allowsMethod(req.Method) && !req.noRetry {
+		attempts = policy.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.clone()
+		attemptReq.Attempt = attempt
+
+		resp, err := c.attempt(ctx, handler, attemptReq)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= attempts || !policy.isRetryable(err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt)
+		level.Debug(*c.logger).Log(
+			"msg", "Request failed, retrying",
+			"path", req.Path,
+			"method", req.Method,
+			"attempt", attempt,
+			"maxAttempts", attempts,
+			"delay", delay,
+			"err", err,
+		)
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// Run a request attempt through the middleware chain, waiting for the rate
+// limiter and for a free slot first if the number of requests in flight is
+// limited.
+func (c *SDTDClient) attempt(ctx context.Context, handler Handler, req *Request) (*RawResponse, error) {
+	if c.limiter != nil {
+		wait, err := c.limiter.wait(ctx, req.Path)
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			level.Debug(*c.logger).Log("msg", "Request delayed by rate limit", "path", req.Path, "method", req.Method, "wait", wait)
+		}
+		req.Wait = wait
+	}
+
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+			defer func() { <-c.inFlight }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return handler(ctx, req)
+}
+
+// Perform a single attempt of a request. This is the innermost handler of the
+// middleware chain. Non 2XX responses are returned along with an APIError.
+func (c *SDTDClient) roundTrip(ctx context.Context, r *Request) (*RawResponse, error) {
+	fullPath, err := url.JoinPath(c.Host, r.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -166,25 +435,46 @@ func (c *SDTDClient) Do(method string, path string, params *url.Values, data []b
 		return nil, err
 	}
 
-	if params != nil {
-		baseUrl.RawQuery = params.Encode()
+	if r.Params != nil {
+		baseUrl.RawQuery = r.Params.Encode()
+	}
+
+	var reqBody io.Reader
+	if r.Body != nil {
+		reqBody = bytes.NewReader(r.Body)
 	}
 
-	req, err := http.NewRequest(method, baseUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, r.Method, baseUrl.String(), reqBody)
 	if err != nil {
 		return nil, err
 	}
-	req.Header = headers
+	req.Header = r.Header
 
-	if data != nil {
-		req.Body = io.NopCloser(bytes.NewReader(data))
+	httpClient := c.client
+	if r.stream {
+		// A stream stays open indefinitely, so it must not be subject to the
+		// client's request timeout.
+		streamClient := *c.client
+		streamClient.Timeout = 0
+		httpClient = &streamClient
 	}
 
-	level.Debug(*c.logger).Log("url", baseUrl.String(), "method", method)
-	resp, err := c.client.Do(req)
+	level.Debug(*c.logger).Log("url", baseUrl.String(), "method", r.Method)
+	start := time.Now()
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	if r.stream && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		level.Debug(*c.logger).Log("url", baseUrl.String(), "method", r.Method, "statusCode", resp.StatusCode)
+		return &RawResponse{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Header:     resp.Header,
+			Latency:    time.Since(start),
+			stream:     resp.Body,
+		}, nil
+	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
@@ -192,43 +482,87 @@ func (c *SDTDClient) Do(method string, path string, params *url.Values, data []b
 		return nil, err
 	}
 
-	level.Debug(*c.logger).Log("url", baseUrl.String(), "method", method, "statusCode", resp.StatusCode)
+	rawResp := &RawResponse{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+		Latency:    time.Since(start),
+	}
+
+	level.Debug(*c.logger).Log("url", baseUrl.String(), "method", r.Method, "statusCode", resp.StatusCode)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		level.Warn(*c.logger).Log("status", resp.Status, "statusCode", resp.StatusCode, "body", body)
-		return nil, ErrNon2XXResponse
+		level.Debug(*c.logger).Log("status", resp.Status, "statusCode", resp.StatusCode, "body", body)
+		return rawResp, newAPIError(r.Method, resp, body)
 	}
 
-	return body, nil
+	return rawResp, nil
 }
 
-// Attempt to connect to the API and verify the credentials. Also determines if
-// Alloc's server fixes are available.
+// Attempt to connect to the API and verify the credentials. Also discovers the
+// server's capabilities, including whether Alloc's server fixes are available.
 func (c *SDTDClient) Connect() error {
-	if _, err := c.GetServerInfo(); err != nil {
+	return c.ConnectContext(context.Background())
+}
+
+// Attempt to connect to the API using the given context and verify the
+// credentials. Also discovers the server's capabilities, including whether
+// Alloc's server fixes are available.
+func (c *SDTDClient) ConnectContext(ctx context.Context) error {
+	info, err := c.GetServerInfoContext(ctx)
+	if err != nil {
 		return err
 	}
+
+	caps := &Capabilities{Modules: map[string]AllowedVerbs{}}
+	caps.ServerVersion, caps.ServerBuild = parseServerVersion(info)
+
+	status, err := c.GetUserStatusContext(ctx)
+	if err != nil && !errors.Is(err, ErrNon2XXResponse) {
+		return err
+	} else if err != nil {
+		level.Warn(*c.logger).Log("msg", "Failed to retrieve the user status, module capabilities are unknown", "err", err)
+	} else {
+		caps.Modules = modulesFromPermissions(status.Data.Permissions)
+	}
 	level.Debug(*c.logger).Log("msg", "Server responded, checking for Alloc's Server Fixes APIs")
 
 	path := "/api/getstats"
-	err := Get(c, path, &ServerStatsResponse{}, nil)
+	err = GetContext(ctx, c, path, &ServerStatsResponse{}, nil)
 	if err != nil && !errors.Is(err, ErrNon2XXResponse) {
 		return err
 	} else if err != nil {
 		level.Warn(*c.logger).Log("msg", "Failed to detect Alloc's Server Fixes API")
 	} else {
 		level.Info(*c.logger).Log("msg", "Alloc's Server Fixes detected")
-		c.allocsEnabled = true
+		caps.AllocsServerFixes = true
+		caps.ModEndpoints = []string{path}
+		for _, endpoint := range allocsEndpoints {
+			if endpoint != path && caps.HasModule("webapi."+strings.TrimPrefix(endpoint, "/api/")) {
+				caps.ModEndpoints = append(caps.ModEndpoints, endpoint)
+			}
+		}
 	}
 
+	c.mu.Lock()
+	c.allocsEnabled = caps.AllocsServerFixes
+	c.capabilities = caps
+	c.mu.Unlock()
+
 	return nil
 }
 
 // Returns statistics for the server (current game time and number of players,
 // animals, and hostiles).
 func (c *SDTDClient) GetServerStats() (*ServerStatsResponse, error) {
+	return c.GetServerStatsContext(context.Background())
+}
+
+// Context-aware variant of GetServerStats.
+func (c *SDTDClient) GetServerStatsContext(ctx context.Context) (*ServerStatsResponse, error) {
 	path := "/api/serverstats"
 	status := ServerStatsResponse{}
-	err := Get(c, path, &status, nil)
+	err := GetContext(ctx, c, path, &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -237,9 +571,14 @@ func (c *SDTDClient) GetServerStats() (*ServerStatsResponse, error) {
 
 // Returns the server configuration.
 func (c *SDTDClient) GetServerInfo() (*ServerInfoResponse, error) {
+	return c.GetServerInfoContext(context.Background())
+}
+
+// Context-aware variant of GetServerInfo.
+func (c *SDTDClient) GetServerInfoContext(ctx context.Context) (*ServerInfoResponse, error) {
 	path := "/api/serverinfo"
 	status := ServerInfoResponse{}
-	err := Get(c, path, &status, nil)
+	err := GetContext(ctx, c, path, &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -248,9 +587,14 @@ func (c *SDTDClient) GetServerInfo() (*ServerInfoResponse, error) {
 
 // Returns the game preferences.
 func (c *SDTDClient) GetGamePrefs() (*GamePrefsResponse, error) {
+	return c.GetGamePrefsContext(context.Background())
+}
+
+// Context-aware variant of GetGamePrefs.
+func (c *SDTDClient) GetGamePrefsContext(ctx context.Context) (*GamePrefsResponse, error) {
 	path := "/api/gameprefs"
 	status := GamePrefsResponse{}
-	err := Get(c, path, &status, nil)
+	err := GetContext(ctx, c, path, &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -258,9 +602,14 @@ func (c *SDTDClient) GetGamePrefs() (*GamePrefsResponse, error) {
 }
 
 func (c *SDTDClient) GetUserStatus() (*UserStatusResponse, error) {
+	return c.GetUserStatusContext(context.Background())
+}
+
+// Context-aware variant of GetUserStatus.
+func (c *SDTDClient) GetUserStatusContext(ctx context.Context) (*UserStatusResponse, error) {
 	path := "userstatus"
 	status := UserStatusResponse{}
-	err := Get(c, path, &status, nil)
+	err := GetContext(ctx, c, path, &status, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -269,15 +618,52 @@ func (c *SDTDClient) GetUserStatus() (*UserStatusResponse, error) {
 
 // Returns the list of players currently online.
 func (c *SDTDClient) GetOnlinePlayers() (*PlayersResponse, error) {
+	return c.GetOnlinePlayersContext(context.Background())
+}
+
+// Context-aware variant of GetOnlinePlayers.
+func (c *SDTDClient) GetOnlinePlayersContext(ctx context.Context) (*PlayersResponse, error) {
 	path := "/api/player"
 	players := PlayersResponse{}
-	err := Get(c, path, &players, nil)
+	err := GetContext(ctx, c, path, &players, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &players, nil
 }
 
+// Returns the hostile entities currently alive.
+func (c *SDTDClient) GetHostiles() (*EntitiesResponse, error) {
+	return c.GetHostilesContext(context.Background())
+}
+
+// Context-aware variant of GetHostiles.
+func (c *SDTDClient) GetHostilesContext(ctx context.Context) (*EntitiesResponse, error) {
+	path := "/api/hostile"
+	hostiles := EntitiesResponse{}
+	err := GetContext(ctx, c, path, &hostiles, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &hostiles, nil
+}
+
+// Returns the animals currently alive.
+func (c *SDTDClient) GetAnimals() (*EntitiesResponse, error) {
+	return c.GetAnimalsContext(context.Background())
+}
+
+// Context-aware variant of GetAnimals.
+func (c *SDTDClient) GetAnimalsContext(ctx context.Context) (*EntitiesResponse, error) {
+	path := "/api/animal"
+	animals := EntitiesResponse{}
+	err := GetContext(ctx, c, path, &animals, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &animals, nil
+}
+
 // Get an amount of lines from the server log
 //
 // count is the number of lines to fetch. If negative fetches count lines from
@@ -287,6 +673,11 @@ func (c *SDTDClient) GetOnlinePlayers() (*PlayersResponse, error) {
 // log line if count is positive. Defaults to the most recent log line if count
 // is negative.
 func (c *SDTDClient) GetLog(count *int, firstLine *int) (*LogResponse, error) {
+	return c.GetLogContext(context.Background(), count, firstLine)
+}
+
+// Context-aware variant of GetLog.
+func (c *SDTDClient) GetLogContext(ctx context.Context, count *int, firstLine *int) (*LogResponse, error) {
 	path := "/api/log"
 	params := url.Values{}
 	if count != nil {
@@ -297,7 +688,7 @@ func (c *SDTDClient) GetLog(count *int, firstLine *int) (*LogResponse, error) {
 	}
 
 	log := LogResponse{}
-	err := Get(c, path, &log, &params)
+	err := GetContext(ctx, c, path, &log, &params)
 	if err != nil {
 		return nil, err
 	}
@@ -305,19 +696,35 @@ func (c *SDTDClient) GetLog(count *int, firstLine *int) (*LogResponse, error) {
 }
 
 // Fetch a list of all whitelisted users / groups.
-func (c *SDTDClient) GetWhitelist() error {
-	return nil
+func (c *SDTDClient) GetWhitelist() (*WhitelistResponse, error) {
+	return c.GetWhitelistContext(context.Background())
+}
+
+// Context-aware variant of GetWhitelist.
+func (c *SDTDClient) GetWhitelistContext(ctx context.Context) (*WhitelistResponse, error) {
+	path := "/api/whitelist"
+	whitelist := WhitelistResponse{}
+	err := GetContext(ctx, c, path, &whitelist, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &whitelist, nil
 }
 
 // Add a user to the whitelist.
 func (c *SDTDClient) AddWhitelistUser(id string, name string) error {
+	return c.AddWhitelistUserContext(context.Background(), id, name)
+}
+
+// Context-aware variant of AddWhitelistUser.
+func (c *SDTDClient) AddWhitelistUserContext(ctx context.Context, id string, name string) error {
 	path := fmt.Sprintf("/api/whitelist/user/%v", id)
 	data := WhitelistRequestBody{name}
 	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	err = Post(c, path, &BaseResponse{}, nil, body)
+	err = PostContext(ctx, c, path, &BaseResponse{}, nil, body)
 	if err != nil {
 		return err
 	}
@@ -326,8 +733,48 @@ func (c *SDTDClient) AddWhitelistUser(id string, name string) error {
 
 // Remove a user from the whitelist.
 func (c *SDTDClient) DeleteWhitelistUser(id string) error {
+	return c.DeleteWhitelistUserContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteWhitelistUser.
+func (c *SDTDClient) DeleteWhitelistUserContext(ctx context.Context, id string) error {
 	path := fmt.Sprintf("/api/whitelist/user/%v", id)
-	err := Delete(c, path, &BaseResponse{}, nil, nil)
+	err := DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Add a Steam group to the whitelist.
+func (c *SDTDClient) AddWhitelistGroup(id string, name string) error {
+	return c.AddWhitelistGroupContext(context.Background(), id, name)
+}
+
+// Context-aware variant of AddWhitelistGroup.
+func (c *SDTDClient) AddWhitelistGroupContext(ctx context.Context, id string, name string) error {
+	path := fmt.Sprintf("/api/whitelist/group/%v", id)
+	data := WhitelistRequestBody{name}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove a Steam group from the whitelist.
+func (c *SDTDClient) DeleteWhitelistGroup(id string) error {
+	return c.DeleteWhitelistGroupContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteWhitelistGroup.
+func (c *SDTDClient) DeleteWhitelistGroupContext(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/whitelist/group/%v", id)
+	err := DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
 	if err != nil {
 		return err
 	}
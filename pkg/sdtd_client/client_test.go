@@ -0,0 +1,167 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// A fake API server recording the highest number of requests it handled at
+// the same time.
+type fakeServer struct {
+	*httptest.Server
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	requests atomic.Int32
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	fs := &fakeServer{}
+	responses := map[string]string{
+		"/api/serverinfo": `{"data":[{"name":"Version","type":"string","value":"V 1.0 (b333)"}]}`,
+		"/userstatus": `{"data":{"permissionLevel":0,"permissions":[
+			{"module":"webapi.serverinfo","allowed":{"GET":true}},
+			{"module":"webapi.serverstats","allowed":{"GET":true}}]}}`,
+		"/api/serverstats": `{"data":{"gameTime":{"days":1,"hours":2,"minutes":3},"players":1,"hostiles":2,"animals":3}}`,
+	}
+
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := fs.inFlight.Add(1)
+		defer fs.inFlight.Add(-1)
+		fs.requests.Add(1)
+		for {
+			peak := fs.peak.Load()
+			if current <= peak || fs.peak.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+
+		// Hold the request so that concurrent requests overlap.
+		time.Sleep(2 * time.Millisecond)
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+// Run fn from many goroutines at once, several times each.
+func hammer(t *testing.T, goroutines int, iterations int, fn func(ctx context.Context, worker int) error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for worker := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				if err := fn(ctx, worker); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	fs := newFakeServer(t)
+	client, err := sdtdclient.NewSDTDClientWithOptions(
+		fs.URL,
+		&sdtdclient.SDTDAuth{TokenName: "name", TokenSecret: "secret"},
+		sdtdclient.WithPermissionChecks(),
+		sdtdclient.WithResponseCache(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	hammer(t, 16, 20, func(ctx context.Context, worker int) error {
+		switch worker % 4 {
+		case 0:
+			return client.ConnectContext(ctx)
+		case 1:
+			_, err := client.DoContext(ctx, "GET", "/api/serverstats", nil, nil)
+			return err
+		case 2:
+			caps := client.Capabilities()
+			if caps == nil || !caps.HasModule(sdtdclient.ModuleServerStats) {
+				t.Errorf("capabilities missing the server stats module: %+v", caps)
+			}
+			client.AllocsEnabled()
+			return nil
+		default:
+			client.SetRetryPolicy(sdtdclient.DefaultRetryPolicy())
+			client.Use(func(next sdtdclient.Handler) sdtdclient.Handler { return next })
+			_, err := client.GetServerStatsContext(ctx)
+			return err
+		}
+	})
+}
+
+func TestClientMaxInFlight(t *testing.T) {
+	for _, limit := range []int{1, 3, 8} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			fs := newFakeServer(t)
+			client, err := sdtdclient.NewSDTDClientWithOptions(
+				fs.URL,
+				&sdtdclient.SDTDAuth{TokenName: "name", TokenSecret: "secret"},
+				sdtdclient.WithMaxInFlight(limit),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hammer(t, 4*limit, 5, func(ctx context.Context, worker int) error {
+				if worker%2 == 0 {
+					return client.ConnectContext(ctx)
+				}
+				_, err := client.DoContext(ctx, "GET", "/api/serverstats", nil, nil)
+				return err
+			})
+
+			if peak := int(fs.peak.Load()); peak > limit {
+				t.Errorf("%d requests in flight at once, want at most %d", peak, limit)
+			} else if peak < limit {
+				t.Logf("only %d of %d allowed requests were in flight at once", peak, limit)
+			}
+			if fs.requests.Load() == 0 {
+				t.Error("no request reached the server")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Receivers for running console commands.
+
+// Run a console command (e.g. "lp" or "say hello") and return its output.
+// Falls back to Alloc's Server Fixes when the server does not provide the
+// vanilla command module.
+func (c *SDTDClient) ExecuteCommand(command string) (*CommandResultData, error) {
+	return c.ExecuteCommandContext(context.Background(), command)
+}
+
+// Context-aware variant of ExecuteCommand.
+func (c *SDTDClient) ExecuteCommandContext(ctx context.Context, command string) (*CommandResultData, error) {
+	if c.moduleMissing(ModuleCommand) && c.AllocsEnabled() {
+		return c.ExecuteCommandMContext(ctx, command)
+	}
+
+	path := "/api/command"
+	body, err := json.Marshal(CommandRequestBody{command})
+	if err != nil {
+		return nil, err
+	}
+
+	result := CommandResultResponse{}
+	err = PostContext(ctx, c, path, &result, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// Returns the console commands available on the server.
+func (c *SDTDClient) GetCommands() (*CommandsResponse, error) {
+	return c.GetCommandsContext(context.Background())
+}
+
+// Context-aware variant of GetCommands.
+func (c *SDTDClient) GetCommandsContext(ctx context.Context) (*CommandsResponse, error) {
+	path := "/api/command"
+	commands := CommandsResponse{}
+	err := GetContext(ctx, c, path, &commands, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &commands, nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Typed builders and receivers for common admin console commands. Arguments
+// are validated and quoted so that player names and messages cannot alter the
+// command.
+
+// Identifies a player in a console command.
+type PlayerRef interface {
+	// Returns the quoted command argument for the player.
+	commandArg() (string, error)
+}
+
+// A player's platform ID, e.g. "Steam_76561198000000000" or "EOS_0002...".
+type PlatformID string
+
+// A player's entity ID.
+type EntityID int
+
+// A player's name.
+type PlayerName string
+
+var platformIDRe = regexp.MustCompile(`^(Steam|XBL|PSN|EOS|Local)_[0-9A-Za-z]+$`)
+
+func (id PlatformID) commandArg() (string, error) {
+	if !platformIDRe.MatchString(string(id)) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlatformID, id)
+	}
+	return string(id), nil
+}
+
+func (id EntityID) commandArg() (string, error) {
+	return strconv.Itoa(int(id)), nil
+}
+
+func (name PlayerName) commandArg() (string, error) {
+	return QuoteCommandArg(string(name))
+}
+
+// Interprets a string as a player reference: an entity ID if it is numeric, a
+// platform ID if it has a known platform prefix, or a player name otherwise.
+func ParsePlayerRef(s string) PlayerRef {
+	if id, err := strconv.Atoi(s); err == nil {
+		return EntityID(id)
+	}
+	if platformIDRe.MatchString(s) {
+		return PlatformID(s)
+	}
+	return PlayerName(s)
+}
+
+// Quote an argument for use in a console command. The console has no escape
+// sequences, so arguments containing double quotes or control characters
+// (which could end the argument or start another command) are rejected with
+// ErrUnsafeArgument.
+func QuoteCommandArg(arg string) (string, error) {
+	if strings.ContainsFunc(arg, func(r rune) bool { return r == '"' || unicode.IsControl(r) }) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafeArgument, arg)
+	}
+	return `"` + arg + `"`, nil
+}
+
+// Build a command from its name and arguments, quoting the optional trailing
+// text argument if it is not empty.
+func buildCommand(name string, args []string, text string) (string, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	if text == "" {
+		return command, nil
+	}
+
+	quoted, err := QuoteCommandArg(text)
+	if err != nil {
+		return "", err
+	}
+	return command + " " + quoted, nil
+}
+
+// Build a command kicking a player, with an optional reason shown to them.
+func KickCommand(player PlayerRef, reason string) (string, error) {
+	target, err := player.commandArg()
+	if err != nil {
+		return "", err
+	}
+	return buildCommand("kick", []string{target}, reason)
+}
+
+// Ban duration units understood by the ban command, largest first. Durations
+// that none of them divides are given in minutes.
+var banUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"weeks", 7 * 24 * time.Hour},
+	{"days", 24 * time.Hour},
+	{"hours", time.Hour},
+}
+
+// Build a command banning a player for the given duration, with an optional
+// reason. The duration is expressed in the largest unit that divides it
+// exactly, rounding up to whole minutes.
+func BanCommand(player PlayerRef, duration time.Duration, reason string) (string, error) {
+	target, err := player.commandArg()
+	if err != nil {
+		return "", err
+	}
+	if duration <= 0 {
+		return "", ErrInvalidDuration
+	}
+
+	duration = (duration + time.Minute - 1).Truncate(time.Minute)
+	for _, unit := range banUnits {
+		if duration%unit.duration == 0 {
+			count := strconv.FormatInt(int64(duration/unit.duration), 10)
+			return buildCommand("ban add", []string{target, count, unit.name}, reason)
+		}
+	}
+	minutes := strconv.FormatInt(int64(duration/time.Minute), 10)
+	return buildCommand("ban add", []string{target, minutes, "minutes"}, reason)
+}
+
+// Build a command teleporting a player to the given location.
+func TeleportPlayerCommand(player PlayerRef, location Location) (string, error) {
+	target, err := player.commandArg()
+	if err != nil {
+		return "", err
+	}
+	return buildCommand("teleportplayer", []string{
+		target,
+		strconv.Itoa(location.X),
+		strconv.Itoa(location.Y),
+		strconv.Itoa(location.Z),
+	}, "")
+}
+
+// Build a command giving a player count items. A quality of 0 uses the item's
+// default quality.
+func GiveCommand(player PlayerRef, item string, count int, quality int) (string, error) {
+	target, err := player.commandArg()
+	if err != nil {
+		return "", err
+	}
+	itemArg, err := QuoteCommandArg(item)
+	if err != nil {
+		return "", err
+	}
+	if count < 1 {
+		return "", fmt.Errorf("%w: count must be positive", ErrInvalidArgument)
+	}
+
+	args := []string{target, itemArg, strconv.Itoa(count)}
+	if quality > 0 {
+		args = append(args, strconv.Itoa(quality))
+	}
+	return buildCommand("give", args, "")
+}
+
+// Build a command sending a chat message to all players.
+func SayCommand(message string) (string, error) {
+	if message == "" {
+		return "", fmt.Errorf("%w: empty message", ErrInvalidArgument)
+	}
+	return buildCommand("say", nil, message)
+}
+
+// Run a command built by one of the builders.
+func (c *SDTDClient) executeBuilt(ctx context.Context, command string, err error) (*CommandResultData, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c.ExecuteCommandContext(ctx, command)
+}
+
+// Kick a player from the server, with an optional reason shown to them.
+func (c *SDTDClient) Kick(player PlayerRef, reason string) (*CommandResultData, error) {
+	return c.KickContext(context.Background(), player, reason)
+}
+
+// Context-aware variant of Kick.
+func (c *SDTDClient) KickContext(ctx context.Context, player PlayerRef, reason string) (*CommandResultData, error) {
+	command, err := KickCommand(player, reason)
+	return c.executeBuilt(ctx, command, err)
+}
+
+// Ban a player for the given duration, with an optional reason.
+func (c *SDTDClient) Ban(player PlayerRef, duration time.Duration, reason string) (*CommandResultData, error) {
+	return c.BanContext(context.Background(), player, duration, reason)
+}
+
+// Context-aware variant of Ban.
+func (c *SDTDClient) BanContext(ctx context.Context, player PlayerRef, duration time.Duration, reason string) (*CommandResultData, error) {
+	command, err := BanCommand(player, duration, reason)
+	return c.executeBuilt(ctx, command, err)
+}
+
+// Teleport a player to the given location.
+func (c *SDTDClient) TeleportPlayer(player PlayerRef, location Location) (*CommandResultData, error) {
+	return c.TeleportPlayerContext(context.Background(), player, location)
+}
+
+// Context-aware variant of TeleportPlayer.
+func (c *SDTDClient) TeleportPlayerContext(ctx context.Context, player PlayerRef, location Location) (*CommandResultData, error) {
+	command, err := TeleportPlayerCommand(player, location)
+	return c.executeBuilt(ctx, command, err)
+}
+
+// Give a player count items of the given quality (0 for the default quality).
+func (c *SDTDClient) Give(player PlayerRef, item string, count int, quality int) (*CommandResultData, error) {
+	return c.GiveContext(context.Background(), player, item, count, quality)
+}
+
+// Context-aware variant of Give.
+func (c *SDTDClient) GiveContext(ctx context.Context, player PlayerRef, item string, count int, quality int) (*CommandResultData, error) {
+	command, err := GiveCommand(player, item, count, quality)
+	return c.executeBuilt(ctx, command, err)
+}
+
+// Send a chat message to all players.
+func (c *SDTDClient) Say(message string) (*CommandResultData, error) {
+	return c.SayContext(context.Background(), message)
+}
+
+// Context-aware variant of Say.
+func (c *SDTDClient) SayContext(ctx context.Context, message string) (*CommandResultData, error) {
+	command, err := SayCommand(message)
+	return c.executeBuilt(ctx, command, err)
+}
+
+// Save the world.
+func (c *SDTDClient) SaveWorld() (*CommandResultData, error) {
+	return c.SaveWorldContext(context.Background())
+}
+
+// Context-aware variant of SaveWorld.
+func (c *SDTDClient) SaveWorldContext(ctx context.Context) (*CommandResultData, error) {
+	return c.ExecuteCommandContext(ctx, "saveworld")
+}
+
+// Shut the server down.
+func (c *SDTDClient) Shutdown() (*CommandResultData, error) {
+	return c.ShutdownContext(context.Background())
+}
+
+// Context-aware variant of Shutdown.
+func (c *SDTDClient) ShutdownContext(ctx context.Context) (*CommandResultData, error) {
+	return c.ExecuteCommandContext(ctx, "shutdown")
+}
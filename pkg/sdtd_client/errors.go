@@ -0,0 +1,92 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error returned when the API responds with a non 2XX status code. APIError
+// matches ErrNon2XXResponse with errors.Is, as well as one of the status
+// specific sentinels (ErrUnauthorized, ErrForbidden, ErrNotFound or
+// ErrServerError) when applicable.
+type APIError struct {
+	Method     string // HTTP method of the failed request
+	Path       string // URL path of the failed request
+	StatusCode int    // Status code returned by the server
+	Status     string // Status line returned by the server
+	Body       []byte // Raw response body
+	Message    string // Error message provided by the server, if any
+}
+
+// The error payload returned by the vanilla web API.
+type errorResponse struct {
+	Meta struct {
+		ErrorCode        string `json:"errorCode"`
+		ExceptionMessage string `json:"exceptionMessage"`
+	} `json:"meta"`
+}
+
+// Build an APIError from a response, extracting the server provided error
+// message from the body when present.
+func newAPIError(method string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Method:     method,
+		Path:       resp.Request.URL.Path,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+
+	errResp := errorResponse{}
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		apiErr.Message = errResp.Meta.ErrorCode
+		if errResp.Meta.ExceptionMessage != "" {
+			if apiErr.Message != "" {
+				apiErr.Message += ": "
+			}
+			apiErr.Message += errResp.Meta.ExceptionMessage
+		}
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Reports whether the error matches the given target sentinel.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNon2XXResponse:
+		return true
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
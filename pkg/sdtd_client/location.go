@@ -15,9 +15,20 @@ limitations under the License.
 */
 package sdtdclient
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // Returns a location as a string coordinate.
 func (l Location) GetCoordinates() string {
 	return fmt.Sprintf("(%d, %d, %d)", l.X, l.Y, l.Z)
 }
+
+// Returns the straight line distance between two locations, in blocks.
+func (l Location) DistanceTo(other Location) float64 {
+	dx := float64(l.X - other.X)
+	dy := float64(l.Y - other.Y)
+	dz := float64(l.Z - other.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+// Follows the server log by polling GetLog from the last line seen, delivering
+// each entry exactly once. A server restart, detected by line numbers going
+// backwards, makes the follower start over from the oldest line of the new
+// log.
+type LogFollower struct {
+	Interval  time.Duration // Delay between polls once caught up. Defaults to 2 seconds.
+	BatchSize int           // Maximum number of lines fetched per poll. Defaults to 100.
+
+	client   *SDTDClient
+	nextLine atomic.Int64 // -1 until the starting line is known
+	mu       sync.Mutex
+	err      error
+}
+
+// Create a follower starting at the given line, e.g. the NextLine saved by a
+// previous follower. A nil startLine starts at the end of the log, delivering
+// only lines written from now on.
+func (c *SDTDClient) NewLogFollower(startLine *int) *LogFollower {
+	f := &LogFollower{
+		Interval:  2 * time.Second,
+		BatchSize: 100,
+		client:    c,
+	}
+	if startLine != nil {
+		f.nextLine.Store(int64(*startLine))
+	} else {
+		f.nextLine.Store(-1)
+	}
+	return f
+}
+
+// Returns the number of the next line to deliver, suitable as a checkpoint to
+// resume from. Returns -1 if the follower has not fetched the log yet and was
+// created without a start line.
+func (f *LogFollower) NextLine() int {
+	return int(f.nextLine.Load())
+}
+
+// Poll the log and call handle for each new entry, in order, until ctx is
+// cancelled (returning nil) or a request or handle fails (returning the
+// error). An entry is considered delivered once handle returns nil. Fails with
+// ErrInvalidArgument if Interval or BatchSize is not positive.
+func (f *LogFollower) Run(ctx context.Context, handle func(LogEntry) error) error {
+	if f.Interval <= 0 || f.BatchSize <= 0 {
+		return fmt.Errorf("%w: the poll interval and batch size must be positive", ErrInvalidArgument)
+	}
+
+	if f.NextLine() < 0 {
+		count := -1
+		log, err := f.client.GetLogContext(ctx, &count, nil)
+		if err != nil {
+			return ignoreCancel(ctx, err)
+		}
+		f.nextLine.Store(int64(log.Data.LastLine))
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		full, err := f.poll(ctx, handle)
+		if err != nil {
+			return ignoreCancel(ctx, err)
+		}
+
+		// Keep fetching right away while there may be more lines pending.
+		if full {
+			timer.Reset(0)
+		} else {
+			timer.Reset(f.Interval)
+		}
+	}
+}
+
+// Fetch and deliver the next batch of lines. Reports whether the batch was
+// full, meaning more lines may be available.
+func (f *LogFollower) poll(ctx context.Context, handle func(LogEntry) error) (bool, error) {
+	count := f.BatchSize
+	nextLine := f.NextLine()
+	log, err := f.client.GetLogContext(ctx, &count, &nextLine)
+	if err != nil {
+		return false, err
+	}
+
+	if log.Data.LastLine < nextLine {
+		level.Info(*f.client.logger).Log(
+			"msg", "Log line numbers went backwards, the server restarted",
+			"expectedLine", nextLine,
+			"lastLine", log.Data.LastLine,
+		)
+		f.nextLine.Store(0)
+		return true, nil
+	}
+	if nextLine > 0 && log.Data.FirstLine > nextLine {
+		level.Warn(*f.client.logger).Log(
+			"msg", "Log lines were dropped by the server before they could be fetched",
+			"expectedLine", nextLine,
+			"firstLine", log.Data.FirstLine,
+		)
+	}
+
+	for _, entry := range log.Data.Entries {
+		if entry.ID < nextLine {
+			continue
+		}
+		if err := handle(entry); err != nil {
+			return false, err
+		}
+		nextLine = entry.ID + 1
+		f.nextLine.Store(int64(nextLine))
+	}
+	if log.Data.LastLine > nextLine {
+		f.nextLine.Store(int64(log.Data.LastLine))
+	}
+
+	return len(log.Data.Entries) >= count, nil
+}
+
+// Run the follower in a goroutine, delivering the entries on the returned
+// channel. The channel is closed when ctx is cancelled or following fails, in
+// which case Err reports the failure.
+func (f *LogFollower) Entries(ctx context.Context) <-chan LogEntry {
+	entries := make(chan LogEntry)
+	go func() {
+		defer close(entries)
+		err := f.Run(ctx, func(entry LogEntry) error {
+			select {
+			case entries <- entry:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+
+		f.mu.Lock()
+		f.err = err
+		f.mu.Unlock()
+	}()
+	return entries
+}
+
+// Returns the error that stopped a follower started with Entries, or nil.
+func (f *LogFollower) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// Return nil if the error is due to the context being cancelled.
+func ignoreCancel(ctx context.Context, err error) error {
+	if ctx.Err() != nil && isContextError(err) {
+		return nil
+	}
+	return err
+}
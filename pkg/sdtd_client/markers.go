@@ -0,0 +1,93 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Returns the custom markers of the web map.
+func (c *SDTDClient) GetMapMarkers() (*MapMarkersResponse, error) {
+	return c.GetMapMarkersContext(context.Background())
+}
+
+// Context-aware variant of GetMapMarkers.
+func (c *SDTDClient) GetMapMarkersContext(ctx context.Context) (*MapMarkersResponse, error) {
+	path := "/api/markers"
+	markers := MapMarkersResponse{}
+	err := GetContext(ctx, c, path, &markers, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &markers, nil
+}
+
+// Add a marker to the web map. The ID of the marker is ignored, the returned
+// marker carries the ID assigned by the server.
+func (c *SDTDClient) CreateMapMarker(marker MapMarker) (*MapMarker, error) {
+	return c.CreateMapMarkerContext(context.Background(), marker)
+}
+
+// Context-aware variant of CreateMapMarker.
+func (c *SDTDClient) CreateMapMarkerContext(ctx context.Context, marker MapMarker) (*MapMarker, error) {
+	path := "/api/markers"
+	marker.ID = ""
+	body, err := json.Marshal(marker)
+	if err != nil {
+		return nil, err
+	}
+
+	created := MapMarkerResponse{}
+	err = PostContext(ctx, c, path, &created, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	marker.ID = created.Data.ID
+	return &marker, nil
+}
+
+// Replace the name, icon and position of the marker with the given ID.
+func (c *SDTDClient) UpdateMapMarker(marker MapMarker) error {
+	return c.UpdateMapMarkerContext(context.Background(), marker)
+}
+
+// Context-aware variant of UpdateMapMarker.
+func (c *SDTDClient) UpdateMapMarkerContext(ctx context.Context, marker MapMarker) error {
+	if marker.ID == "" {
+		return fmt.Errorf("%w: the marker ID is not set", ErrInvalidArgument)
+	}
+
+	path := fmt.Sprintf("/api/markers/%v", url.PathEscape(marker.ID))
+	body, err := json.Marshal(marker)
+	if err != nil {
+		return err
+	}
+	return PutContext(ctx, c, path, &BaseResponse{}, nil, body)
+}
+
+// Remove a marker from the web map.
+func (c *SDTDClient) DeleteMapMarker(id string) error {
+	return c.DeleteMapMarkerContext(context.Background(), id)
+}
+
+// Context-aware variant of DeleteMapMarker.
+func (c *SDTDClient) DeleteMapMarkerContext(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/api/markers/%v", url.PathEscape(id))
+	return DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// A single request attempt, as seen by middleware. Middleware may modify the
+// request before passing it on, e.g. to inject headers.
+type Request struct {
+	Method  string
+	Path    string // Path relative to the client's host
+	Params  url.Values
+	Header  http.Header
+	Body    []byte
+	Attempt int           // Starts at 1, incremented on each retry
+	Wait    time.Duration // Time spent waiting for the rate limiter
+
+	noRetry bool // Send the request once, whatever the retry policy
+	noCache bool // Bypass the response cache even for GET requests
+	stream  bool // Leave the body of a successful response unread, without timeout
+}
+
+// The response to a request attempt, as seen by middleware.
+type RawResponse struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+	Latency    time.Duration // Time from sending the request to reading the body
+
+	stream io.ReadCloser // Unread body of a streamed response, nil otherwise
+}
+
+// Performs a request attempt. The response is returned along with an APIError
+// when the server responds with a non 2XX status code, and is nil when the
+// server could not be reached.
+type Handler func(ctx context.Context, req *Request) (*RawResponse, error)
+
+// Wraps a Handler, e.g. to inspect or modify requests and responses, or to
+// short-circuit them in tests.
+type Middleware func(next Handler) Handler
+
+// Append middleware to the client's chain. The first middleware added is the
+// outermost one. Middleware wraps each attempt, so retried requests pass
+// through it once per attempt.
+func (c *SDTDClient) Use(middleware ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.middleware = append(c.middleware, middleware...)
+
+	handler := Handler(c.roundTrip)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	c.handler = handler
+}
+
+// Return a copy of the request that can be modified without affecting the
+// original.
+func (r *Request) clone() *Request {
+	req := *r
+	req.Header = r.Header.Clone()
+	if r.Params != nil {
+		req.Params = make(url.Values, len(r.Params))
+		for key, values := range r.Params {
+			req.Params[key] = slices.Clone(values)
+		}
+	}
+	return &req
+}
+
+// Headers whose values are hidden by DumpMiddleware.
+var redactedHeaders = []string{"X-SDTD-API-SECRET"}
+
+// Returns middleware that logs each request and response, including headers and
+// bodies, at debug level. The token secret is redacted.
+func DumpMiddleware(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			headers := req.Header.Clone()
+			for _, name := range redactedHeaders {
+				if _, ok := headers[name]; ok {
+					headers[name] = []string{"REDACTED"}
+				}
+			}
+			level.Debug(*logger).Log(
+				"msg", "Request",
+				"method", req.Method,
+				"path", req.Path,
+				"params", req.Params.Encode(),
+				"headers", headers,
+				"body", req.Body,
+				"attempt", req.Attempt,
+				"wait", req.Wait,
+			)
+
+			resp, err := next(ctx, req)
+			if resp != nil {
+				level.Debug(*logger).Log(
+					"msg", "Response",
+					"method", req.Method,
+					"path", req.Path,
+					"status", resp.Status,
+					"headers", resp.Header,
+					"body", resp.Body,
+					"latency", resp.Latency,
+				)
+			}
+			if err != nil {
+				level.Debug(*logger).Log("msg", "Request failed", "method", req.Method, "path", req.Path, "err", err)
+			}
+			return resp, err
+		}
+	}
+}
+
+// Returns middleware that logs the outcome and latency of each request at info
+// level.
+func LatencyMiddleware(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			keyvals := []any{
+				"method", req.Method,
+				"path", req.Path,
+				"attempt", req.Attempt,
+				"wait", req.Wait,
+				"latency", time.Since(start),
+			}
+			if resp != nil {
+				keyvals = append(keyvals, "statusCode", resp.StatusCode)
+			}
+			if err != nil {
+				keyvals = append(keyvals, "err", err)
+			}
+			level.Info(*logger).Log(keyvals...)
+			return resp, err
+		}
+	}
+}
+
+// Identifies a group of requests counted by a RequestCounter. StatusCode is 0
+// for requests that did not receive a response.
+type RequestCountKey struct {
+	Method     string
+	Path       string
+	StatusCode int
+}
+
+// Counts requests by method, path and status code. Use Middleware to attach it
+// to a client. A RequestCounter is safe for concurrent use.
+type RequestCounter struct {
+	mu     sync.Mutex
+	counts map[RequestCountKey]int
+}
+
+func NewRequestCounter() *RequestCounter {
+	return &RequestCounter{counts: map[RequestCountKey]int{}}
+}
+
+// Returns middleware that records each request attempt in the counter.
+func (rc *RequestCounter) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			resp, err := next(ctx, req)
+
+			key := RequestCountKey{Method: req.Method, Path: req.Path}
+			if resp != nil {
+				key.StatusCode = resp.StatusCode
+			} else {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					key.StatusCode = apiErr.StatusCode
+				}
+			}
+
+			rc.mu.Lock()
+			rc.counts[key]++
+			rc.mu.Unlock()
+			return resp, err
+		}
+	}
+}
+
+// Returns a snapshot of the counts.
+func (rc *RequestCounter) Counts() map[RequestCountKey]int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return maps.Clone(rc.counts)
+}
+
+// Returns the total number of requests counted.
+func (rc *RequestCounter) Total() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	total := 0
+	for _, count := range rc.counts {
+		total += count
+	}
+	return total
+}
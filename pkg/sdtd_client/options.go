@@ -0,0 +1,325 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+// Configures an SDTDClient created by NewSDTDClientWithOptions.
+type Option func(*clientConfig) error
+
+type clientConfig struct {
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	tlsConfig   *tls.Config
+	proxy       func(*http.Request) (*url.URL, error)
+	timeout     time.Duration
+	userAgent   string
+	logger      *log.Logger
+	retryPolicy *RetryPolicy
+	middleware  []Middleware
+	maxInFlight int
+	rateLimiter *rateLimiter
+	cache       *responseCache
+
+	permissionChecks bool
+
+	// Set when an option customizes the transport built by the client, which
+	// cannot be combined with WithHTTPClient.
+	transportSet bool
+}
+
+// Use the given HTTP client for all requests. Cannot be combined with options
+// that configure the transport (WithTransport, WithTLSConfig, WithSSLVerify,
+// WithCACertFile, WithClientCertificate and WithProxy).
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *clientConfig) error {
+		cfg.httpClient = client
+		return nil
+	}
+}
+
+// Use the given round tripper instead of the default transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cfg *clientConfig) error {
+		cfg.transport = transport
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Set the timeout of each request, including reading the response body. Zero
+// means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		cfg.timeout = timeout
+		return nil
+	}
+}
+
+// Set the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(cfg *clientConfig) error {
+		cfg.userAgent = userAgent
+		return nil
+	}
+}
+
+// Use the given TLS configuration. Options applied afterwards (WithSSLVerify,
+// WithCACertFile and WithClientCertificate) modify a clone of it.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(cfg *clientConfig) error {
+		cfg.tlsConfig = tlsConfig.Clone()
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Enable or disable verification of the server's certificate.
+func WithSSLVerify(sslVerify bool) Option {
+	return func(cfg *clientConfig) error {
+		cfg.tls().InsecureSkipVerify = !sslVerify
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Verify the server's certificate against the PEM encoded CA certificates in
+// the given file instead of the system roots.
+func WithCACertFile(path string) Option {
+	return func(cfg *clientConfig) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%w: %s", ErrInvalidCACert, path)
+		}
+		cfg.tls().RootCAs = pool
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Present the given PEM encoded certificate and key to the server.
+func WithClientCertificate(certFile string, keyFile string) Option {
+	return func(cfg *clientConfig) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		cfg.tls().Certificates = append(cfg.tls().Certificates, cert)
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Send requests through the given proxy URL. By default the proxy is taken
+// from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func WithProxy(proxyUrl string) Option {
+	return func(cfg *clientConfig) error {
+		parsed, err := url.Parse(proxyUrl)
+		if err != nil {
+			return err
+		}
+		cfg.proxy = http.ProxyURL(parsed)
+		cfg.transportSet = true
+		return nil
+	}
+}
+
+// Use the given logger. Defaults to a logger that discards everything.
+func WithLogger(logger *log.Logger) Option {
+	return func(cfg *clientConfig) error {
+		cfg.logger = logger
+		return nil
+	}
+}
+
+// Use the given retry policy. A nil policy disables retries. Defaults to
+// DefaultRetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(cfg *clientConfig) error {
+		cfg.retryPolicy = policy
+		return nil
+	}
+}
+
+// Wrap each request with the given middleware, in order. See
+// SDTDClient.Use.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(cfg *clientConfig) error {
+		cfg.middleware = append(cfg.middleware, middleware...)
+		return nil
+	}
+}
+
+// Limit the number of requests the client runs at the same time. Requests
+// beyond the limit wait for a free slot. Zero means no limit.
+func WithMaxInFlight(n int) Option {
+	return func(cfg *clientConfig) error {
+		if n < 0 {
+			return ErrInvalidMaxInFlight
+		}
+		cfg.maxInFlight = n
+		return nil
+	}
+}
+
+// Limit the client to rate requests per second on average, allowing bursts of
+// up to burst requests. Requests over the limit wait for their turn unless
+// WithRateLimitFailFast is used.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(cfg *clientConfig) error {
+		if rate <= 0 {
+			return ErrInvalidRateLimit
+		}
+		cfg.limiter().global = newTokenBucket(rate, burst)
+		return nil
+	}
+}
+
+// Limit requests to paths starting with the given prefix (e.g. "/api/log")
+// separately. These requests are not counted against the client-wide limit.
+// When several prefixes match, the longest one applies.
+func WithEndpointRateLimit(pathPrefix string, rate float64, burst int) Option {
+	return func(cfg *clientConfig) error {
+		if rate <= 0 {
+			return ErrInvalidRateLimit
+		}
+		cfg.limiter().endpoints[normalizePath(pathPrefix)] = newTokenBucket(rate, burst)
+		return nil
+	}
+}
+
+// Fail requests over the rate limit with ErrRateLimited instead of waiting.
+func WithRateLimitFailFast() Option {
+	return func(cfg *clientConfig) error {
+		cfg.limiter().failFast = true
+		return nil
+	}
+}
+
+// Cache GET responses for the given time. Identical GET requests made while
+// one is already in flight share its response instead of hitting the server.
+// A zero ttl only deduplicates concurrent requests. Successful mutating
+// requests evict the cached responses of the same resource (e.g. any POST to
+// "/api/whitelist/..." evicts "/api/whitelist...").
+func WithResponseCache(ttl time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		cfg.responseCache().defaultTTL = ttl
+		return nil
+	}
+}
+
+// Cache GET responses from paths starting with the given prefix for the given
+// time instead of the default one. Enables the response cache.
+func WithEndpointCacheTTL(pathPrefix string, ttl time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		cfg.responseCache().endpointTTL[normalizePath(pathPrefix)] = ttl
+		return nil
+	}
+}
+
+// Evict the cached responses of the paths starting with one of the given
+// prefixes whenever a mutating request to a path starting with mutatedPrefix
+// succeeds. Enables the response cache.
+func WithCacheInvalidation(mutatedPrefix string, evictPrefixes ...string) Option {
+	return func(cfg *clientConfig) error {
+		cache := cfg.responseCache()
+		mutatedPrefix = normalizePath(mutatedPrefix)
+		cache.invalidations[mutatedPrefix] = append(cache.invalidations[mutatedPrefix], evictPrefixes...)
+		return nil
+	}
+}
+
+// Reject requests the token is not allowed to make with a PermissionError
+// before sending them. The permission table is loaded by Connect, or by the
+// first request if the client is not connected.
+func WithPermissionChecks() Option {
+	return func(cfg *clientConfig) error {
+		cfg.permissionChecks = true
+		return nil
+	}
+}
+
+// Return the response cache, creating it if needed.
+func (cfg *clientConfig) responseCache() *responseCache {
+	if cfg.cache == nil {
+		cfg.cache = newResponseCache()
+	}
+	return cfg.cache
+}
+
+// Return the rate limiter, creating it if needed.
+func (cfg *clientConfig) limiter() *rateLimiter {
+	if cfg.rateLimiter == nil {
+		cfg.rateLimiter = &rateLimiter{endpoints: map[string]*tokenBucket{}}
+	}
+	return cfg.rateLimiter
+}
+
+// Return the TLS configuration, creating it if needed.
+func (cfg *clientConfig) tls() *tls.Config {
+	if cfg.tlsConfig == nil {
+		cfg.tlsConfig = &tls.Config{}
+	}
+	return cfg.tlsConfig
+}
+
+// Build the HTTP client described by the configuration.
+func (cfg *clientConfig) buildHTTPClient() (*http.Client, error) {
+	if cfg.httpClient != nil {
+		if cfg.transportSet {
+			return nil, ErrConflictingOptions
+		}
+		client := *cfg.httpClient
+		if cfg.timeout != 0 {
+			client.Timeout = cfg.timeout
+		}
+		return &client, nil
+	}
+
+	transport := cfg.transport
+	if transport == nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.tlsConfig != nil {
+			tr.TLSClientConfig = cfg.tlsConfig
+		}
+		if cfg.proxy != nil {
+			tr.Proxy = cfg.proxy
+		}
+		transport = tr
+	} else if cfg.tlsConfig != nil || cfg.proxy != nil {
+		return nil, ErrConflictingOptions
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.timeout,
+	}, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Methods a web module permission level can be set for.
+var webModuleMethods = []string{"GET", "POST", "PUT", "DELETE"}
+
+// Returns the permission level required to call the module with the given
+// HTTP method.
+func (m WebModulePermission) LevelFor(method string) int {
+	if level, ok := m.MethodLevels[strings.ToUpper(method)]; ok {
+		return level
+	}
+	return m.PermissionLevel
+}
+
+// Returns the verbs a user with the given permission level is allowed on the
+// module, in the form reported by GetUserStatus. Lower levels grant more
+// permissions.
+func (m WebModulePermission) Permission(level int) Permission {
+	return Permission{
+		Module: m.Module,
+		Allowed: AllowedVerbs{
+			Get:    level <= m.LevelFor("GET"),
+			Post:   level <= m.LevelFor("POST"),
+			Put:    level <= m.LevelFor("PUT"),
+			Delete: level <= m.LevelFor("DELETE"),
+		},
+	}
+}
+
+// Fetch the permission level required by each console command.
+func (c *SDTDClient) GetCommandPermissions() (*CommandPermissionsResponse, error) {
+	return c.GetCommandPermissionsContext(context.Background())
+}
+
+// Context-aware variant of GetCommandPermissions.
+func (c *SDTDClient) GetCommandPermissionsContext(ctx context.Context) (*CommandPermissionsResponse, error) {
+	path := "/api/commandpermission"
+	permissions := CommandPermissionsResponse{}
+	err := GetContext(ctx, c, path, &permissions, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &permissions, nil
+}
+
+// Set the permission level required to run a console command.
+func (c *SDTDClient) SetCommandPermission(command string, level int) error {
+	return c.SetCommandPermissionContext(context.Background(), command, level)
+}
+
+// Context-aware variant of SetCommandPermission.
+func (c *SDTDClient) SetCommandPermissionContext(ctx context.Context, command string, level int) error {
+	path := fmt.Sprintf("/api/commandpermission/%v", url.PathEscape(command))
+	body, err := json.Marshal(PermissionLevelRequestBody{PermissionLevel: level})
+	if err != nil {
+		return err
+	}
+	return PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+}
+
+// Reset the permission level required to run a console command to the
+// default.
+func (c *SDTDClient) ResetCommandPermission(command string) error {
+	return c.ResetCommandPermissionContext(context.Background(), command)
+}
+
+// Context-aware variant of ResetCommandPermission.
+func (c *SDTDClient) ResetCommandPermissionContext(ctx context.Context, command string) error {
+	path := fmt.Sprintf("/api/commandpermission/%v", url.PathEscape(command))
+	return DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+}
+
+// Fetch the permission levels required by each web module.
+func (c *SDTDClient) GetWebModulePermissions() (*WebModulesResponse, error) {
+	return c.GetWebModulePermissionsContext(context.Background())
+}
+
+// Context-aware variant of GetWebModulePermissions.
+func (c *SDTDClient) GetWebModulePermissionsContext(ctx context.Context) (*WebModulesResponse, error) {
+	path := "/api/webmodules"
+	modules := WebModulesResponse{}
+	err := GetContext(ctx, c, path, &modules, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &modules, nil
+}
+
+// Set the permission level required to use a web module. methodLevels
+// optionally overrides the level for individual HTTP methods.
+func (c *SDTDClient) SetWebModulePermission(module string, level int, methodLevels map[string]int) error {
+	return c.SetWebModulePermissionContext(context.Background(), module, level, methodLevels)
+}
+
+// Context-aware variant of SetWebModulePermission.
+func (c *SDTDClient) SetWebModulePermissionContext(ctx context.Context, module string, level int, methodLevels map[string]int) error {
+	levels := map[string]int{}
+	for method, methodLevel := range methodLevels {
+		method = strings.ToUpper(method)
+		if !slices.Contains(webModuleMethods, method) {
+			return fmt.Errorf("%w: unknown method %q", ErrInvalidArgument, method)
+		}
+		levels[method] = methodLevel
+	}
+
+	path := fmt.Sprintf("/api/webmodules/%v", url.PathEscape(module))
+	body, err := json.Marshal(WebModuleRequestBody{PermissionLevel: level, MethodLevels: levels})
+	if err != nil {
+		return err
+	}
+	return PostContext(ctx, c, path, &BaseResponse{}, nil, body)
+}
+
+// Reset the permission levels required to use a web module to the defaults.
+func (c *SDTDClient) ResetWebModulePermission(module string) error {
+	return c.ResetWebModulePermissionContext(context.Background(), module)
+}
+
+// Context-aware variant of ResetWebModulePermission.
+func (c *SDTDClient) ResetWebModulePermissionContext(ctx context.Context, module string) error {
+	path := fmt.Sprintf("/api/webmodules/%v", url.PathEscape(module))
+	return DeleteContext(ctx, c, path, &BaseResponse{}, nil, nil)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Error returned when the token is not allowed to use a verb on a module.
+// Matches ErrPermissionDenied with errors.Is.
+type PermissionError struct {
+	Module string
+	Verb   string
+	Path   string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: %s on module %s (requested %s)", ErrPermissionDenied, e.Verb, e.Module, e.Path)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Reports whether the given HTTP method is allowed.
+func (v AllowedVerbs) Allows(method string) bool {
+	switch method {
+	case "GET", "HEAD":
+		return v.Get
+	case "POST":
+		return v.Post
+	case "PUT":
+		return v.Put
+	case "DELETE":
+		return v.Delete
+	}
+	return false
+}
+
+// Load the token's permission table from the user status and cache it in the
+// client's capabilities.
+func (c *SDTDClient) LoadPermissions() error {
+	return c.LoadPermissionsContext(context.Background())
+}
+
+// Context-aware variant of LoadPermissions.
+func (c *SDTDClient) LoadPermissionsContext(ctx context.Context) error {
+	status, err := c.GetUserStatusContext(ctx)
+	if err != nil {
+		return err
+	}
+	modules := modulesFromPermissions(status.Data.Permissions)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capabilities == nil {
+		c.capabilities = &Capabilities{}
+	} else {
+		caps := *c.capabilities
+		c.capabilities = &caps
+	}
+	c.capabilities.Modules = modules
+	return nil
+}
+
+// Check a request against the known capabilities before sending it. Fails with
+// a CapabilityError if the module serving the path is missing and, when
+// permission checks are enabled, with a PermissionError if the token may not
+// use the method on it. Nothing is checked for paths of unknown modules, or if
+// the server did not report any module.
+func (c *SDTDClient) preflight(ctx context.Context, method string, path string) error {
+	module, ok := matchPathPrefix(endpointModules, path)
+	if !ok {
+		return nil
+	}
+
+	if c.checkPerm && c.Capabilities() == nil {
+		if err := c.LoadPermissionsContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	caps := c.capabilities
+	if caps == nil || len(caps.Modules) == 0 {
+		return nil
+	}
+
+	if !caps.HasModule(module) {
+		return &CapabilityError{Module: module, Path: normalizePath(path)}
+	}
+	if c.checkPerm && !caps.Allowed(module).Allows(method) {
+		return &PermissionError{Module: module, Verb: method, Path: normalizePath(path)}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A token bucket allowing Rate requests per second on average, with bursts of
+// up to Burst requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Refill the bucket for the time elapsed since the last update. Must be called
+// with the lock held.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// Take a token and return how long the caller must wait before using it. When
+// failFast is set, no token is taken if one is not immediately available and
+// ok is false.
+func (b *tokenBucket) reserve(failFast bool) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens < 1 && failFast {
+		return 0, false
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), true
+}
+
+// Return a token taken by reserve that ended up unused.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// Limits the rate of requests made by a client, optionally with separate limits
+// per endpoint path.
+type rateLimiter struct {
+	global    *tokenBucket
+	endpoints map[string]*tokenBucket // Keyed by path prefix
+	failFast  bool
+}
+
+// Return the bucket governing the given path: the one of the longest matching
+// endpoint prefix, falling back to the client-wide bucket (which may be nil).
+func (rl *rateLimiter) bucket(path string) *tokenBucket {
+	if bucket, ok := matchPathPrefix(rl.endpoints, path); ok {
+		return bucket
+	}
+	return rl.global
+}
+
+// Wait until a request to the given path is allowed and return how long it
+// waited. Returns an error wrapping ErrRateLimited when failing fast, or when
+// the wait would exceed the context's deadline.
+func (rl *rateLimiter) wait(ctx context.Context, path string) (time.Duration, error) {
+	bucket := rl.bucket(path)
+	if bucket == nil {
+		return 0, nil
+	}
+
+	delay, ok := bucket.reserve(rl.failFast)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrRateLimited, path)
+	}
+	if delay == 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		bucket.cancel()
+		return 0, fmt.Errorf("%w: waiting %v would exceed the deadline", ErrRateLimited, delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		bucket.cancel()
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
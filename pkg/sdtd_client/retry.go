@@ -0,0 +1,128 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Controls how failed requests are retried. Requests are retried when the
+// server could not be reached or responded with one of RetryableStatusCodes.
+type RetryPolicy struct {
+	// Total number of attempts, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// Delay before the first retry. Each following retry multiplies the delay
+	// by Multiplier, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Fraction (0 to 1) of each delay that is randomized to avoid many clients
+	// retrying in lockstep.
+	Jitter float64
+
+	// Status codes that are considered transient.
+	RetryableStatusCodes []int
+
+	// Also retry mutating requests (POST, PUT, DELETE). Only enable this when
+	// the calls made through the client are safe to repeat.
+	RetryMutating bool
+}
+
+// Returns the retry policy used by new clients: GET requests are attempted up
+// to 3 times, mutating requests are not retried.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// Reports whether requests using the given method may be retried.
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return p.RetryMutating
+}
+
+// Reports whether the given request error is worth retrying.
+func (p *RetryPolicy) isRetryable(err error) bool {
+	if isContextError(err) || errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.RetryableStatusCodes, apiErr.StatusCode)
+	}
+
+	// Any other error comes from the transport (connection refused, reset,
+	// etc.), which is what happens while the server restarts.
+	return true
+}
+
+// Returns the delay to wait before the given retry (starting at 1).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		delay *= p.Multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// Wait for the given delay, returning early with an error if the context is
+// cancelled or its deadline would pass before the delay ends.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+// A server-sent event.
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// Read events from an SSE stream, calling handle for each one until the stream
+// ends or handle returns an error.
+func readSSE(r io.Reader, handle func(sseEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	event := sseEvent{}
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				event.Data = strings.Join(data, "\n")
+				if err := handle(event); err != nil {
+					return err
+				}
+			}
+			event, data = sseEvent{ID: event.ID}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment, used as keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// A subscription to the server log pushed over server-sent events. Entries are
+// delivered on Entries until the subscription is closed or fails, after which
+// the channel is closed and Err reports why.
+type LogStream struct {
+	Entries <-chan LogEntry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	err    error
+}
+
+// Stop the subscription and wait for it to end. Returns the error that ended
+// the subscription, if any.
+func (s *LogStream) Close() error {
+	s.cancel()
+	<-s.done
+	return s.Err()
+}
+
+// Returns the error that ended the subscription, or nil if it is still running
+// or was closed.
+func (s *LogStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Subscribe to log lines pushed by the server. The first connection is made
+// before returning, so an error is returned if the server does not support log
+// events (e.g. ErrNotFound). The subscription then reconnects with backoff
+// whenever the connection drops, until ctx is cancelled or Close is called.
+// Each connection passes through the client's middleware and rate limiter.
+func (c *SDTDClient) SubscribeLog(ctx context.Context) (*LogStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	body, err := c.openEventStream(ctx, "log", "")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	entries := make(chan LogEntry)
+	stream := &LogStream{
+		Entries: entries,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go stream.run(ctx, c, body, entries)
+	return stream, nil
+}
+
+// Read the stream, reconnecting as needed, until the context is cancelled or
+// reconnecting fails for good.
+func (s *LogStream) run(ctx context.Context, c *SDTDClient, body io.ReadCloser, entries chan<- LogEntry) {
+	defer close(s.done)
+	defer close(entries)
+
+	c.mu.RLock()
+	policy := c.retryPolicy
+	c.mu.RUnlock()
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+
+	lastID := ""
+	var serverRetry time.Duration
+	for attempt := 0; ; {
+		if body != nil {
+			attempt = 0
+			err := readSSE(body, func(event sseEvent) error {
+				lastID = event.ID
+				if event.Retry > 0 {
+					serverRetry = event.Retry
+				}
+				if event.Event != "logLine" {
+					return nil
+				}
+
+				entry := LogEntry{}
+				if err := json.Unmarshal([]byte(event.Data), &entry); err != nil {
+					level.Warn(*c.logger).Log("msg", "Failed to decode log event", "data", event.Data, "err", err)
+					return nil
+				}
+				select {
+				case entries <- entry:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			body.Close()
+			body = nil
+
+			if ctx.Err() != nil {
+				return
+			}
+			level.Debug(*c.logger).Log("msg", "Log event stream ended, reconnecting", "err", err)
+		}
+
+		attempt++
+		delay := policy.backoff(attempt)
+		if serverRetry > 0 {
+			delay = serverRetry
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			if ctx.Err() == nil {
+				s.setErr(err)
+			}
+			return
+		}
+
+		var err error
+		body, err = c.openEventStream(ctx, "log", lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			level.Debug(*c.logger).Log("msg", "Failed to reconnect to the log event stream", "attempt", attempt, "err", err)
+			if !policy.isRetryable(err) {
+				s.setErr(err)
+				return
+			}
+		}
+	}
+}
+
+func (s *LogStream) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Open the server-sent events stream for the given events. The request passes
+// through the middleware chain and the rate limiter like any other, but is
+// never retried, as the subscription reconnects by itself, and only occupies a
+// slot of WithMaxInFlight until the server responds.
+func (c *SDTDClient) openEventStream(ctx context.Context, events string, lastEventID string) (io.ReadCloser, error) {
+	req := c.newRequest("GET", "/sse/", &url.Values{"events": {events}}, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	req.noRetry, req.stream = true, true
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.stream
+	if body == nil {
+		// Responses made up by middleware carry their body in full.
+		body = io.NopCloser(bytes.NewReader(resp.Body))
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		body.Close()
+		return nil, fmt.Errorf("%w: server did not respond with an event stream", ErrNotFound)
+	}
+	return body, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+// Log event streams pass through the middleware chain, and do not keep other
+// requests waiting once connected, even with a single request in flight.
+func TestSubscribeLogMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sse/":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("event: logLine\ndata: {\"msg\":\"hello\",\"type\":\"Log\"}\n\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/api/serverstats":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"gameTime":{"days":1,"hours":2,"minutes":3}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sdtdclient.NewSDTDClientWithOptions(
+		server.URL,
+		&sdtdclient.SDTDAuth{TokenName: "name", TokenSecret: "secret"},
+		sdtdclient.WithMaxInFlight(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	client.Use(func(next sdtdclient.Handler) sdtdclient.Handler {
+		return func(ctx context.Context, req *sdtdclient.Request) (*sdtdclient.RawResponse, error) {
+			mu.Lock()
+			seen[req.Path]++
+			mu.Unlock()
+			return next(ctx, req)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.SubscribeLog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	select {
+	case entry := <-stream.Entries:
+		if entry.Msg != "hello" {
+			t.Errorf("got log entry %+v", entry)
+		}
+	case <-ctx.Done():
+		t.Fatal("no log entry received")
+	}
+
+	if _, err := client.GetServerStatsContext(ctx); err != nil {
+		t.Fatalf("request while streaming: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if seen["/sse/"] != 1 {
+		t.Errorf("middleware saw %d event stream requests, want 1", seen["/sse/"])
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// The web map is served as square PNG tiles. At the maximum zoom level one
+// pixel covers one block, each lower zoom level halving the resolution. Tile
+// (x, y) covers the blocks from x*size to (x+1)*size-1 along the X axis and
+// from y*size to (y+1)*size-1 along the Z axis, size being the number of
+// blocks per tile at the zoom level. The top row of a tile image is its
+// northern (highest Z) edge.
+const (
+	MapTileSize    = 128 // Width and height of a tile, in pixels
+	MapMaxZoom     = 4   // Highest zoom level served
+	defaultWorkers = 4   // Tiles fetched concurrently by default
+)
+
+// Identifies a tile of the web map.
+type TileCoord struct {
+	Zoom int
+	X    int
+	Y    int
+}
+
+// A range of tiles at one zoom level, bounds included.
+type TileBounds struct {
+	Zoom int
+	MinX int
+	MinY int
+	MaxX int
+	MaxY int
+}
+
+// A tile of the web map.
+type MapTile struct {
+	TileCoord
+	Data         []byte // PNG image, nil if the tile does not exist
+	ETag         string // Validators for conditional requests
+	LastModified string
+	Cached       bool // Whether Data comes from the tile cache
+}
+
+// Options of FetchMapTiles.
+type TileFetchOptions struct {
+	Concurrency int           // Tiles fetched concurrently. Defaults to 4.
+	Cache       *DirTileCache // Optional cache of the tiles fetched by previous runs
+}
+
+// Returns the number of blocks along the side of a tile at the given zoom
+// level.
+func BlocksPerTile(zoom int) int {
+	return MapTileSize << (MapMaxZoom - zoom)
+}
+
+// Returns the tiles covering the area between two world locations at the
+// given zoom level. The Y coordinates of the locations are ignored.
+func TileBoundsFor(zoom int, from, to Location) (TileBounds, error) {
+	if zoom < 0 || zoom > MapMaxZoom {
+		return TileBounds{}, fmt.Errorf("%w: zoom level %d out of range 0-%d", ErrInvalidArgument, zoom, MapMaxZoom)
+	}
+
+	size := BlocksPerTile(zoom)
+	return TileBounds{
+		Zoom: zoom,
+		MinX: floorDiv(min(from.X, to.X), size),
+		MinY: floorDiv(min(from.Z, to.Z), size),
+		MaxX: floorDiv(max(from.X, to.X), size),
+		MaxY: floorDiv(max(from.Z, to.Z), size),
+	}, nil
+}
+
+// Returns the width and height in pixels of the image covering the tiles.
+func (b TileBounds) Size() (int, int) {
+	return (b.MaxX - b.MinX + 1) * MapTileSize, (b.MaxY - b.MinY + 1) * MapTileSize
+}
+
+// Returns the coordinates of the tiles within the bounds.
+func (b TileBounds) Tiles() []TileCoord {
+	coords := []TileCoord{}
+	for y := b.MaxY; y >= b.MinY; y-- {
+		for x := b.MinX; x <= b.MaxX; x++ {
+			coords = append(coords, TileCoord{Zoom: b.Zoom, X: x, Y: y})
+		}
+	}
+	return coords
+}
+
+// Returns the pixel of the stitched image of the tiles showing the given world
+// location. The Y coordinate of the location is ignored.
+func (b TileBounds) PixelAt(location Location) image.Point {
+	size := float64(BlocksPerTile(b.Zoom))
+	scale := MapTileSize / size
+	return image.Point{
+		X: int((float64(location.X) - float64(b.MinX)*size) * scale),
+		Y: int((float64(b.MaxY+1)*size - float64(location.Z) - 1) * scale),
+	}
+}
+
+// Integer division rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// Fetch a tile of the web map. If cached is not nil, the request is made
+// conditional on the tile having changed since, and cached is returned
+// (marked as such) when it has not. Missing tiles are returned without data.
+func (c *SDTDClient) GetMapTile(coord TileCoord, cached *MapTile) (*MapTile, error) {
+	return c.GetMapTileContext(context.Background(), coord, cached)
+}
+
+// Context-aware variant of GetMapTile.
+func (c *SDTDClient) GetMapTileContext(ctx context.Context, coord TileCoord, cached *MapTile) (*MapTile, error) {
+	path := fmt.Sprintf("/map/%d/%d/%d.png", coord.Zoom, coord.X, coord.Y)
+	header := http.Header{"Accept": []string{"image/png"}}
+	if cached != nil {
+		if cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.getRaw(ctx, path, header)
+	if errors.Is(err, ErrNotFound) {
+		return &MapTile{TileCoord: coord}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		tile := *cached
+		tile.TileCoord = coord
+		tile.Cached = true
+		return &tile, nil
+	}
+	return &MapTile{
+		TileCoord:    coord,
+		Data:         resp.Body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// Fetch the tiles within the bounds, several at a time. The tiles are
+// returned in the order of TileBounds.Tiles. The first failure cancels the
+// remaining requests.
+func (c *SDTDClient) FetchMapTiles(bounds TileBounds, opts TileFetchOptions) ([]MapTile, error) {
+	return c.FetchMapTilesContext(context.Background(), bounds, opts)
+}
+
+// Context-aware variant of FetchMapTiles.
+func (c *SDTDClient) FetchMapTilesContext(ctx context.Context, bounds TileBounds, opts TileFetchOptions) ([]MapTile, error) {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	coords := bounds.Tiles()
+	tiles := make([]MapTile, len(coords))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for range min(workers, len(coords)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				tile, err := c.fetchCachedTile(ctx, coords[idx], opts.Cache)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				tiles[idx] = *tile
+			}
+		}()
+	}
+
+feed:
+	for idx := range coords {
+		select {
+		case indexes <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return tiles, nil
+}
+
+// Fetch a tile, revalidating and updating its cached copy if a cache is given.
+func (c *SDTDClient) fetchCachedTile(ctx context.Context, coord TileCoord, cache *DirTileCache) (*MapTile, error) {
+	if cache == nil {
+		return c.GetMapTileContext(ctx, coord, nil)
+	}
+
+	cached, err := cache.Load(coord)
+	if err != nil {
+		return nil, err
+	}
+	tile, err := c.GetMapTileContext(ctx, coord, cached)
+	if err != nil {
+		return nil, err
+	}
+	if !tile.Cached && tile.Data != nil {
+		if err := cache.Store(tile); err != nil {
+			return nil, err
+		}
+	}
+	return tile, nil
+}
+
+// Stitch tiles into a single image covering the bounds. Missing tiles are left
+// transparent.
+func StitchMapTiles(bounds TileBounds, tiles []MapTile) (*image.RGBA, error) {
+	width, height := bounds.Size()
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for idx := range tiles {
+		tile := &tiles[idx]
+		if tile.Data == nil || tile.Zoom != bounds.Zoom ||
+			tile.X < bounds.MinX || tile.X > bounds.MaxX || tile.Y < bounds.MinY || tile.Y > bounds.MaxY {
+			continue
+		}
+
+		img, err := png.Decode(bytes.NewReader(tile.Data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding tile %d/%d/%d: %w", tile.Zoom, tile.X, tile.Y, err)
+		}
+		origin := image.Point{
+			X: (tile.X - bounds.MinX) * MapTileSize,
+			Y: (bounds.MaxY - tile.Y) * MapTileSize,
+		}
+		rect := image.Rectangle{Min: origin, Max: origin.Add(image.Point{MapTileSize, MapTileSize})}
+		draw.Draw(canvas, rect, img, img.Bounds().Min, draw.Src)
+	}
+	return canvas, nil
+}
+
+// A cache of map tiles in a directory, storing each tile as {zoom}/{x}/{y}.png
+// along with the validators used to revalidate it.
+type DirTileCache struct {
+	Dir string
+}
+
+// Validators of a cached tile.
+type tileMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// Returns the path of a cached tile, without extension.
+func (d *DirTileCache) path(coord TileCoord) string {
+	return filepath.Join(d.Dir, strconv.Itoa(coord.Zoom), strconv.Itoa(coord.X), strconv.Itoa(coord.Y))
+}
+
+// Load a tile from the cache. Returns nil if the tile is not cached.
+func (d *DirTileCache) Load(coord TileCoord) (*MapTile, error) {
+	base := d.path(coord)
+	data, err := os.ReadFile(base + ".png")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	meta := tileMeta{}
+	if raw, err := os.ReadFile(base + ".json"); err == nil {
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("reading cached tile %s: %w", base, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &MapTile{
+		TileCoord:    coord,
+		Data:         data,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+		Cached:       true,
+	}, nil
+}
+
+// Store a tile in the cache.
+func (d *DirTileCache) Store(tile *MapTile) error {
+	base := d.path(tile.TileCoord)
+	if err := os.MkdirAll(filepath.Dir(base), 0o755); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(tileMeta{ETag: tile.ETag, LastModified: tile.LastModified})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".png", tile.Data, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(base+".json", meta, 0o644)
+}
@@ -15,14 +15,33 @@ limitations under the License.
 */
 package sdtdclient
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrNon2XXResponse        = errors.New("received non 2XX status code")
+	ErrUnauthorized          = errors.New("unauthorized, check the token name and secret")
+	ErrForbidden             = errors.New("forbidden, the token lacks the required permission")
+	ErrNotFound              = errors.New("endpoint or resource not found")
+	ErrServerError           = errors.New("server error")
 	ErrNoHostSet             = errors.New("host not set")
 	ErrInvalidHostScheme     = errors.New("the host scheme is invalid, must be http or https")
 	ErrNilAuth               = errors.New("cannot use nil Auth")
+	ErrInvalidCACert         = errors.New("no valid PEM certificates found")
+	ErrConflictingOptions    = errors.New("the HTTP client or transport options conflict with each other")
+	ErrInvalidMaxInFlight    = errors.New("max in-flight requests cannot be negative")
+	ErrInvalidRateLimit      = errors.New("the rate limit must be positive")
+	ErrRateLimited           = errors.New("request rate limit exceeded")
 	ErrAllocsModNotInstalled = errors.New("alloc's server fixes not installed")
+	ErrCapabilityMissing     = errors.New("capability not available")
+	ErrPermissionDenied      = errors.New("permission denied")
+	ErrUnsafeArgument        = errors.New("command argument contains a double quote or control character")
+	ErrInvalidPlatformID     = errors.New("invalid platform ID")
+	ErrInvalidArgument       = errors.New("invalid argument")
+	ErrInvalidDuration       = errors.New("invalid duration")
+	ErrInvalidTimestamp      = errors.New("invalid timestamp")
 )
 
 type BaseResponse struct {
@@ -98,6 +117,92 @@ type Location struct {
 	Z int `json:"z"`
 }
 
+// A hostile or animal entity.
+type EntityData struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"` // Entity class name, e.g. "zombieBoe"
+	Position Location `json:"position"`
+	Health   int      `json:"health"`
+}
+
+type EntitiesResponse struct {
+	BaseResponse
+	Data []EntityData `json:"data"`
+}
+
+// Alloc's Server Fixes Mod variant of the entity list response
+type EntitiesResponseM []EntityData
+
+// A custom marker on the web map.
+type MapMarker struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	Icon     string   `json:"icon,omitempty"` // URL of the marker icon, the default icon if empty
+	Position Location `json:"position"`
+}
+
+type MapMarkersResponse struct {
+	BaseResponse
+	Data []MapMarker `json:"data"`
+}
+
+type MapMarkerResponse struct {
+	BaseResponse
+	Data MapMarker `json:"data"`
+}
+
+// The land claims of a player. Alloc's Server Fixes Mod only.
+type LandClaimOwnerM struct {
+	PlatformID      string     `json:"steamid"`
+	CrossPlatformID string     `json:"crossplatformid"`
+	Name            string     `json:"playername"`
+	Active          bool       `json:"claimactive"` // Whether the claims protect the land, i.e. the owner played recently
+	Claims          []Location `json:"claims"`      // Locations of the land claim blocks
+}
+
+// Alloc's Server Fixes Mod land claims response
+type LandClaimsResponseM struct {
+	ClaimSize   int               `json:"claimsize"` // Width of the square area protected by a claim, in blocks
+	ClaimOwners []LandClaimOwnerM `json:"claimowners"`
+}
+
+// An item stack in a player's inventory. Alloc's Server Fixes Mod only.
+type InventoryItemM struct {
+	Name    string `json:"name"`
+	Count   int    `json:"count"`
+	Quality int    `json:"quality"` // 0 for items without quality levels
+}
+
+// The items worn by a player. Empty slots are nil. Alloc's Server Fixes Mod
+// only.
+type EquipmentM struct {
+	Head     *InventoryItemM `json:"head"`
+	Eyes     *InventoryItemM `json:"eyes"`
+	Face     *InventoryItemM `json:"face"`
+	Armor    *InventoryItemM `json:"armor"`
+	Jacket   *InventoryItemM `json:"jacket"`
+	Shirt    *InventoryItemM `json:"shirt"`
+	LegArmor *InventoryItemM `json:"legarmor"`
+	Pants    *InventoryItemM `json:"pants"`
+	Boots    *InventoryItemM `json:"boots"`
+	Gloves   *InventoryItemM `json:"gloves"`
+}
+
+// Alloc's Server Fixes Mod player inventory response. Empty bag and belt slots
+// are nil.
+type PlayerInventoryM struct {
+	PlatformID      string            `json:"userid"`
+	CrossPlatformID string            `json:"crossplatformid"`
+	EntityID        int               `json:"entityid"`
+	Name            string            `json:"playername"`
+	Bag             []*InventoryItemM `json:"bag"`
+	Belt            []*InventoryItemM `json:"belt"`
+	Equipment       EquipmentM        `json:"equipment"`
+}
+
+// Alloc's Server Fixes Mod response with the inventories of all players
+type PlayerInventoriesResponseM []PlayerInventoryM
+
 type KillsData struct {
 	Zombies int `json:"zombies"`
 	Players int `json:"players"`
@@ -184,10 +289,167 @@ type WhitelistUser struct {
 	UserID string `json:"userId"`
 }
 
+// A whitelisted Steam group.
+type WhitelistGroup struct {
+	Name            string `json:"name"`
+	GroupID         string `json:"groupId"`
+	PermissionLevel int    `json:"permissionLevel"` // Level granted to group members, if reported by the server
+}
+
+type WhitelistData struct {
+	Users  []WhitelistUser  `json:"users"`
+	Groups []WhitelistGroup `json:"groups"`
+}
+
+type WhitelistResponse struct {
+	BaseResponse
+	Data WhitelistData `json:"data"`
+}
+
 type WhitelistRequestBody struct {
 	Name string `json:"name"`
 }
 
+// A user granted an admin permission level.
+type AdminUser struct {
+	Name            string `json:"name"`
+	UserID          string `json:"userId"`
+	PermissionLevel int    `json:"permissionLevel"`
+}
+
+// A Steam group granted admin permission levels.
+type AdminGroup struct {
+	Name                  string `json:"name"`
+	GroupID               string `json:"groupId"`
+	PermissionLevelNormal int    `json:"permissionLevelNormal"` // Level of regular group members
+	PermissionLevelMods   int    `json:"permissionLevelMods"`   // Level of group officers
+}
+
+type AdminsData struct {
+	Users  []AdminUser  `json:"users"`
+	Groups []AdminGroup `json:"groups"`
+}
+
+type AdminsResponse struct {
+	BaseResponse
+	Data AdminsData `json:"data"`
+}
+
+type AdminUserRequestBody struct {
+	Name            string `json:"name"`
+	PermissionLevel int    `json:"permissionLevel"`
+}
+
+type AdminGroupRequestBody struct {
+	Name                  string `json:"name"`
+	PermissionLevelNormal int    `json:"permissionLevelNormal"`
+	PermissionLevelMods   int    `json:"permissionLevelMods"`
+}
+
+// A ban on the server's blacklist.
+type BanEntry struct {
+	Name   string    `json:"name"`
+	UserID string    `json:"userId"`
+	Until  time.Time `json:"bannedUntil"`
+	Reason string    `json:"banReason"`
+}
+
+type BlacklistData struct {
+	Bans []BanEntry `json:"bans"`
+}
+
+type BlacklistResponse struct {
+	BaseResponse
+	Data BlacklistData `json:"data"`
+}
+
+type BlacklistRequestBody struct {
+	Name   string `json:"name"`
+	Until  string `json:"bannedUntil"` // RFC 3339 timestamp
+	Reason string `json:"banReason"`
+}
+
+// The permission level required to run a console command.
+type CommandPermission struct {
+	Command         string `json:"command"`
+	PermissionLevel int    `json:"permissionLevel"`
+	IsDefault       bool   `json:"isDefault"` // Whether the level is the command's default
+}
+
+type CommandPermissionsData struct {
+	Commands []CommandPermission `json:"commands"`
+}
+
+type CommandPermissionsResponse struct {
+	BaseResponse
+	Data CommandPermissionsData `json:"data"`
+}
+
+// The permission levels required to use a web module. Lower levels grant more
+// permissions.
+type WebModulePermission struct {
+	Module          string         `json:"module"`
+	PermissionLevel int            `json:"permissionLevel"`        // Level required for methods without a specific level
+	MethodLevels    map[string]int `json:"methodLevels,omitempty"` // Level required per HTTP method
+	IsDefault       bool           `json:"isDefault"`              // Whether the levels are the module's defaults
+}
+
+type WebModulesData struct {
+	Modules []WebModulePermission `json:"modules"`
+}
+
+type WebModulesResponse struct {
+	BaseResponse
+	Data WebModulesData `json:"data"`
+}
+
+type PermissionLevelRequestBody struct {
+	PermissionLevel int `json:"permissionLevel"`
+}
+
+type WebModuleRequestBody struct {
+	PermissionLevel int            `json:"permissionLevel"`
+	MethodLevels    map[string]int `json:"methodLevels,omitempty"`
+}
+
+type CommandRequestBody struct {
+	Command string `json:"command"`
+}
+
+// The result of a console command.
+type CommandResultData struct {
+	Command    string `json:"command"`    // The command that was run
+	Parameters string `json:"parameters"` // The parameters passed to the command
+	Result     string `json:"result"`     // The output of the command
+}
+
+type CommandResultResponse struct {
+	BaseResponse
+	Data CommandResultData `json:"data"`
+}
+
+// Alloc's Server Fixes Mod variant of the command result response
+type CommandResultResponseM struct {
+	CommandResultData
+}
+
+// A console command available on the server.
+type CommandInfo struct {
+	Command     string   `json:"command"`     // Primary name of the command
+	Overloads   []string `json:"overloads"`   // All names the command can be called by
+	Description string   `json:"description"` // One line description
+	Help        string   `json:"help"`        // Detailed help text
+}
+
+type CommandsData struct {
+	Commands []CommandInfo `json:"commands"`
+}
+
+type CommandsResponse struct {
+	BaseResponse
+	Data CommandsData `json:"data"`
+}
+
 type Response interface {
 	BaseResponse |
 		ServerInfoResponse |
@@ -196,5 +458,20 @@ type Response interface {
 		PlayersResponse |
 		PlayersResponseM |
 		LogResponse |
-		GamePrefsResponse
+		GamePrefsResponse |
+		CommandResultResponse |
+		CommandResultResponseM |
+		CommandsResponse |
+		WhitelistResponse |
+		BlacklistResponse |
+		AdminsResponse |
+		CommandPermissionsResponse |
+		WebModulesResponse |
+		EntitiesResponse |
+		EntitiesResponseM |
+		MapMarkersResponse |
+		MapMarkerResponse |
+		LandClaimsResponseM |
+		PlayerInventoryM |
+		PlayerInventoriesResponseM
 }
@@ -15,7 +15,13 @@ limitations under the License.
 */
 package sdtdclient
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func SecondsToDaysHoursMinutesSeconds(playtime int) string {
 	var days, hours, minutes, seconds int
@@ -32,3 +38,95 @@ func SecondsToDaysHoursMinutesSeconds(playtime int) string {
 
 	return fmt.Sprintf("%d:%02d:%02d:%02d", days, hours, minutes, seconds)
 }
+
+// A number followed by a unit, e.g. "12h" or "1.5d".
+var durationPartRe = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)([a-zµμ]+)`)
+
+// Parse a duration such as "7d", "2w" or "1d12h". In addition to the units
+// understood by time.ParseDuration, accepts "d" for days and "w" for weeks.
+// The parts may be given in any order, e.g. "12h1d".
+func ParseDuration(s string) (time.Duration, error) {
+	rest, sign := s, time.Duration(1)
+	if strings.HasPrefix(rest, "-") {
+		rest, sign = rest[1:], -1
+	} else {
+		rest = strings.TrimPrefix(rest, "+")
+	}
+	if rest == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	var total time.Duration
+	for rest != "" {
+		m := durationPartRe.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		rest = rest[len(m[0]):]
+
+		var unit time.Duration
+		switch m[2] {
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(m[0])
+			if err != nil {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+			}
+			total += d
+			continue
+		}
+		count, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		total += time.Duration(count * float64(unit))
+	}
+	return sign * total, nil
+}
+
+// Timestamp layouts used by the server, tried in order by ParseTimestamp.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"1/2/2006 3:04:05 PM",
+}
+
+// Parse a timestamp reported by the server. Timestamps without a time zone
+// are taken to be in UTC. An empty string yields the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+}
+
+// Return the path with a single leading slash.
+func normalizePath(path string) string {
+	return "/" + strings.TrimPrefix(path, "/")
+}
+
+// Return the value of the longest key of m that prefixes the given path.
+func matchPathPrefix[V any](m map[string]V, path string) (V, bool) {
+	path = normalizePath(path)
+
+	var match V
+	matchLen := -1
+	for prefix, value := range m {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matchLen {
+			match, matchLen = value, len(prefix)
+		}
+	}
+	return match, matchLen >= 0
+}
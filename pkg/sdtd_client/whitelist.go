@@ -0,0 +1,119 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// The changes needed to bring the live whitelist in line with a desired one.
+// Entries to add also cover existing entries whose name changed, since adding
+// an entry again replaces it. Desired entries without a name never rename an
+// existing entry.
+type WhitelistPlan struct {
+	AddUsers     []WhitelistUser
+	RemoveUsers  []WhitelistUser
+	AddGroups    []WhitelistGroup
+	RemoveGroups []WhitelistGroup
+}
+
+// Compute the changes turning the current whitelist into the desired one.
+// Entries missing from the desired whitelist are only removed if prune is set.
+func PlanWhitelist(current, desired *WhitelistData, prune bool) *WhitelistPlan {
+	plan := &WhitelistPlan{}
+
+	users := map[string]WhitelistUser{}
+	for _, user := range current.Users {
+		users[user.UserID] = user
+	}
+	wantUsers := map[string]bool{}
+	for _, user := range desired.Users {
+		wantUsers[user.UserID] = true
+		if existing, ok := users[user.UserID]; !ok || renamed(existing.Name, user.Name) {
+			plan.AddUsers = append(plan.AddUsers, user)
+		}
+	}
+
+	groups := map[string]WhitelistGroup{}
+	for _, group := range current.Groups {
+		groups[group.GroupID] = group
+	}
+	wantGroups := map[string]bool{}
+	for _, group := range desired.Groups {
+		wantGroups[group.GroupID] = true
+		if existing, ok := groups[group.GroupID]; !ok || renamed(existing.Name, group.Name) {
+			plan.AddGroups = append(plan.AddGroups, group)
+		}
+	}
+
+	if prune {
+		for _, user := range current.Users {
+			if !wantUsers[user.UserID] {
+				plan.RemoveUsers = append(plan.RemoveUsers, user)
+			}
+		}
+		for _, group := range current.Groups {
+			if !wantGroups[group.GroupID] {
+				plan.RemoveGroups = append(plan.RemoveGroups, group)
+			}
+		}
+	}
+
+	return plan
+}
+
+// Reports whether a desired entry names an existing entry differently.
+func renamed(current, desired string) bool {
+	return desired != "" && current != desired
+}
+
+// Reports whether the plan makes no changes.
+func (p *WhitelistPlan) Empty() bool {
+	return len(p.AddUsers) == 0 && len(p.RemoveUsers) == 0 &&
+		len(p.AddGroups) == 0 && len(p.RemoveGroups) == 0
+}
+
+// Apply the changes of a plan to the whitelist, stopping at the first change
+// that fails.
+func (c *SDTDClient) ApplyWhitelistPlan(plan *WhitelistPlan) error {
+	return c.ApplyWhitelistPlanContext(context.Background(), plan)
+}
+
+// Context-aware variant of ApplyWhitelistPlan.
+func (c *SDTDClient) ApplyWhitelistPlanContext(ctx context.Context, plan *WhitelistPlan) error {
+	for _, user := range plan.AddUsers {
+		if err := c.AddWhitelistUserContext(ctx, user.UserID, user.Name); err != nil {
+			return fmt.Errorf("adding user %s: %w", user.UserID, err)
+		}
+	}
+	for _, group := range plan.AddGroups {
+		if err := c.AddWhitelistGroupContext(ctx, group.GroupID, group.Name); err != nil {
+			return fmt.Errorf("adding group %s: %w", group.GroupID, err)
+		}
+	}
+	for _, user := range plan.RemoveUsers {
+		if err := c.DeleteWhitelistUserContext(ctx, user.UserID); err != nil {
+			return fmt.Errorf("removing user %s: %w", user.UserID, err)
+		}
+	}
+	for _, group := range plan.RemoveGroups {
+		if err := c.DeleteWhitelistGroupContext(ctx, group.GroupID); err != nil {
+			return fmt.Errorf("removing group %s: %w", group.GroupID, err)
+		}
+	}
+	return nil
+}
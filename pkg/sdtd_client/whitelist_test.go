@@ -0,0 +1,102 @@
+/*
+Copyright © 2024 Tom Helander thomas.helander@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sdtdclient_test
+
+import (
+	"reflect"
+	"testing"
+
+	sdtdclient "github.com/thelande/sdtd_client/pkg/sdtd_client"
+)
+
+func TestPlanWhitelist(t *testing.T) {
+	steve := sdtdclient.WhitelistUser{Name: "Steve", UserID: "Steam_1"}
+	alice := sdtdclient.WhitelistUser{Name: "Alice", UserID: "EOS_2"}
+	clan := sdtdclient.WhitelistGroup{Name: "Clan", GroupID: "103582791429521412"}
+	current := &sdtdclient.WhitelistData{
+		Users:  []sdtdclient.WhitelistUser{steve, alice},
+		Groups: []sdtdclient.WhitelistGroup{clan},
+	}
+
+	for _, test := range []struct {
+		name    string
+		desired *sdtdclient.WhitelistData
+		prune   bool
+		want    *sdtdclient.WhitelistPlan
+	}{
+		{
+			name:    "no-op",
+			desired: current,
+			prune:   true,
+			want:    &sdtdclient.WhitelistPlan{},
+		},
+		{
+			name: "add",
+			desired: &sdtdclient.WhitelistData{
+				Users:  []sdtdclient.WhitelistUser{steve, alice, {Name: "Bob", UserID: "XBL_3"}},
+				Groups: []sdtdclient.WhitelistGroup{clan, {Name: "Allies", GroupID: "103582791429521413"}},
+			},
+			want: &sdtdclient.WhitelistPlan{
+				AddUsers:  []sdtdclient.WhitelistUser{{Name: "Bob", UserID: "XBL_3"}},
+				AddGroups: []sdtdclient.WhitelistGroup{{Name: "Allies", GroupID: "103582791429521413"}},
+			},
+		},
+		{
+			name: "rename",
+			desired: &sdtdclient.WhitelistData{
+				Users:  []sdtdclient.WhitelistUser{{Name: "Steven", UserID: "Steam_1"}, alice},
+				Groups: []sdtdclient.WhitelistGroup{{Name: "Clan 2", GroupID: clan.GroupID}},
+			},
+			want: &sdtdclient.WhitelistPlan{
+				AddUsers:  []sdtdclient.WhitelistUser{{Name: "Steven", UserID: "Steam_1"}},
+				AddGroups: []sdtdclient.WhitelistGroup{{Name: "Clan 2", GroupID: clan.GroupID}},
+			},
+		},
+		{
+			name:    "no prune",
+			desired: &sdtdclient.WhitelistData{Users: []sdtdclient.WhitelistUser{steve}},
+			want:    &sdtdclient.WhitelistPlan{},
+		},
+		{
+			name:    "prune",
+			desired: &sdtdclient.WhitelistData{Users: []sdtdclient.WhitelistUser{steve}},
+			prune:   true,
+			want: &sdtdclient.WhitelistPlan{
+				RemoveUsers:  []sdtdclient.WhitelistUser{alice},
+				RemoveGroups: []sdtdclient.WhitelistGroup{clan},
+			},
+		},
+		{
+			name: "empty names",
+			desired: &sdtdclient.WhitelistData{
+				Users:  []sdtdclient.WhitelistUser{{UserID: "Steam_1"}, {UserID: "EOS_2"}},
+				Groups: []sdtdclient.WhitelistGroup{{GroupID: clan.GroupID}},
+			},
+			prune: true,
+			want:  &sdtdclient.WhitelistPlan{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			plan := sdtdclient.PlanWhitelist(current, test.desired, test.prune)
+			if !reflect.DeepEqual(plan, test.want) {
+				t.Errorf("got %+v, want %+v", plan, test.want)
+			}
+			if plan.Empty() != reflect.DeepEqual(test.want, &sdtdclient.WhitelistPlan{}) {
+				t.Errorf("Empty() = %v for %+v", plan.Empty(), plan)
+			}
+		})
+	}
+}